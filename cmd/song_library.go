@@ -1,11 +1,24 @@
 package main
 
 import (
-	"github.com/orungrau/em_song_library/internal/app"
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/cli"
 	"github.com/orungrau/em_song_library/internal/config"
+	"os"
 )
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
 	cfg := config.MustLoad()
-	app.MustRun(cfg)
+
+	if err := cli.Run(cfg, os.Args[1:]); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
 }
@@ -1,51 +1,276 @@
 package app
 
 import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/auth"
 	"github.com/orungrau/em_song_library/internal/config"
+	"github.com/orungrau/em_song_library/internal/domain/model"
 	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/eventbus"
+	"github.com/orungrau/em_song_library/internal/health"
+	"github.com/orungrau/em_song_library/internal/metrics"
+	"github.com/orungrau/em_song_library/internal/repository/postgres"
+	"github.com/orungrau/em_song_library/internal/repository/storage/apikey"
+	"github.com/orungrau/em_song_library/internal/repository/storage/audit"
+	"github.com/orungrau/em_song_library/internal/repository/storage/changefeed"
+	"github.com/orungrau/em_song_library/internal/repository/storage/outbox"
 	"github.com/orungrau/em_song_library/internal/repository/storage/song"
+	"github.com/orungrau/em_song_library/internal/repository/storage/webhook"
+	"github.com/orungrau/em_song_library/internal/tracing"
+	"github.com/orungrau/em_song_library/internal/transport/graphql"
+	"github.com/orungrau/em_song_library/internal/transport/grpc"
 	"github.com/orungrau/em_song_library/internal/transport/http"
 	"github.com/orungrau/em_song_library/internal/transport/http/handlers"
+	"github.com/orungrau/em_song_library/internal/transport/http/middleware"
+	webhooksender "github.com/orungrau/em_song_library/internal/webhook"
+	"github.com/orungrau/em_song_library/pkg/cache"
 	"github.com/orungrau/em_song_library/pkg/logger"
+	"github.com/orungrau/em_song_library/pkg/ratelimit"
 	"github.com/orungrau/em_song_library/pkg/transport"
 	"github.com/rs/zerolog"
+	nethttp "net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
 func MustRun(cfg *config.AppConfig) {
-	// Config Logger
-	// TODO: Use config
-	log := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().
-		Timestamp().
-		Str("role", "song_library_service").
-		Logger()
-	log = log.Hook(logger.TracingHook{})
+	log := NewLogger()
 	log.Debug().Msg("Service startup")
 
+	// Config tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.TracingConfig, nil)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not set up tracing")
+	}
+
+	// Config metrics
+	appMetrics := metrics.New()
+
+	// Config database
+	db := postgres.NewDatabase(log, &cfg.PostgresConfig)
+	db.MustConnect()
+	db.MustMigrate()
+	appMetrics.RegisterPool(db.Pool())
+
 	// Config song storage
-	songStorage := song.NewPostgresStorage(log, &cfg.PostgresConfig)
-	songStorage.MustConnect()
-	songStorage.MustAutoMigrate()
+	songStorage := song.NewPostgresStorage(log, db)
+
+	// Config song cache
+	var songCache *song.CachedStorage
+	storage := song.NewInstrumentedStorage(songStorage, appMetrics)
+	if cfg.SongCache.GetEnabled() {
+		songCache = song.NewCachedStorage(
+			storage,
+			cache.NewLocal[*model.Song](cfg.SongCache.GetSize(), cfg.SongCache.GetTTL()),
+			cache.NewLocal[[]*model.Song](cfg.SongCache.GetListSize(), cfg.SongCache.GetTTL()),
+			appMetrics,
+		)
+		storage = songCache
+	}
+
+	// Config audit log
+	auditService := service.NewAuditService(log, audit.NewPostgresStorage(log, db))
 
 	// Config song service
-	songService := service.NewSongService(log, songStorage)
+	policy, err := NewPolicy(&cfg.AuthConfig)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not load authorization policy")
+	}
+	changeFeedService := service.NewChangeFeedService(log, changefeed.NewPostgresStorage(log, db), policy)
+	webhookService := service.NewWebhookService(log, webhook.NewPostgresStorage(log, db), webhooksender.NewHTTPSender(cfg.Webhook.GetAllowPrivateNetworks()), &cfg.Webhook)
+	// In-process consumers subscribe to the memory sink when it is enabled
+	eventChannel := eventbus.NewChannelSink()
+	eventSinks, err := NewEventSinks(log, &cfg.Outbox, eventChannel)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid outbox configuration")
+	}
+	// Webhook deliveries are queued from the relayed events
+	eventSinks = append(eventSinks, webhookService)
+	outboxService := service.NewOutboxService(log, outbox.NewPostgresStorage(log, db), &cfg.Outbox, eventSinks...)
+	// Suggestions are dropped on every local change and on changes in the feed
+	suggestCache := cache.NewLRU[string, []*model.Suggestion](cfg.SuggestConfig.GetCacheSize(), cfg.SuggestConfig.GetCacheTTL())
+	songService := service.NewTracedSongService(service.NewAuthorizedSongService(
+		service.NewSongService(log, storage, db, suggestCache, auditService, changeFeedService, service.NewSongEventHook(outboxService)),
+		policy,
+	))
 
-	// Config song handler
-	songHandler := handlers.NewSongHandler(songService)
+	// Config API key service
+	apiKeyService := service.NewAPIKeyService(log, apikey.NewPostgresStorage(log, db))
+
+	// Config bearer token authentication
+	var bearer auth.BearerAuthenticator
+	if cfg.AuthConfig.JWT.GetEnabled() {
+		bearer = auth.NewJWTAuthenticator(mustNewJWKS(log, &cfg.AuthConfig.JWT), &cfg.AuthConfig.JWT)
+	}
+	authenticator := auth.NewAuthenticator(cfg.AuthConfig.GetEnabled(), cfg.AuthConfig.GetAnonymousRole(), apiKeyService, bearer)
+	authMiddleware := middleware.NewAuthMiddleware(authenticator)
+
+	// Config rate limiting, both transports draw from the same buckets
+	rateLimitStore := ratelimit.NewMemoryStore()
+	rateLimit, err := middleware.NewRateLimitMiddleware(log, rateLimitStore, &cfg.RateLimitConfig)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid rate limit configuration")
+	}
+	grpcRateLimit, err := grpc.NewRateLimiter(log, rateLimitStore, &cfg.RateLimitConfig)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid rate limit configuration")
+	}
+
+	// Config response compression and caching headers
+	compression, err := middleware.NewCompressionMiddleware(&cfg.Compression)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid compression configuration")
+	}
+	cacheControl, err := middleware.NewCacheControlMiddleware(&cfg.HTTPCache)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid cache configuration")
+	}
+
+	// Config browser access
+	cors, err := middleware.NewCORSMiddleware(&cfg.CORS)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid CORS configuration")
+	}
+	securityHeaders := middleware.NewSecurityHeadersMiddleware(&cfg.SecurityHeaders)
+
+	// Config handlers
+	songHandler := handlers.NewSongHandler(songService, changeFeedService, &cfg.HTTPCache)
+	adminHandler := handlers.NewAdminHandler(db)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	changeFeedHandler := handlers.NewChangeFeedHandler(changeFeedService, &cfg.ChangeFeed)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+
+	// Config health checks
+	healthRegistry := health.NewRegistry(cfg.HealthConfig.GetCheckTimeout())
+	healthRegistry.Register(health.NewChecker("postgres", db.Ping), false)
+	healthRegistry.Register(health.NewChecker("migrations", db.CheckSchema), false)
+	healthHandler := handlers.NewHealthHandler(healthRegistry)
 
 	// Setup router
-	router := http.NewRouter(log, songHandler, cfg.HttpServer.GetAddress())
+	router := http.NewRouter(log, http.Handlers{
+		Song:       songHandler,
+		Admin:      adminHandler,
+		Health:     healthHandler,
+		APIKey:     apiKeyHandler,
+		Audit:      auditHandler,
+		ChangeFeed: changeFeedHandler,
+		Webhook:    webhookHandler,
+		GraphQL:    graphql.NewHandler(log, songService),
+	}, authMiddleware, rateLimit, compression, cacheControl, cors, securityHeaders, appMetrics, &cfg.HttpServer)
 
 	// Start server
-	server := transport.NewHTTPServer(log, router, &cfg.HttpServer)
+	server, err := transport.NewHTTPServer(log, router, &cfg.HttpServer)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid HTTP server configuration")
+	}
 	server.MustStart()
 
+	// Start gRPC server
+	var grpcServer *transport.GRPCServer
+	if cfg.GRPCServer.GetEnabled() {
+		grpcOptions, err := transport.GRPCServerOptions(log, &cfg.GRPCServer)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid gRPC server configuration")
+		}
+		grpcServer = transport.NewGRPCServer(log, grpc.NewServer(log, songService, authenticator, grpcRateLimit, appMetrics, grpcOptions...), &cfg.GRPCServer)
+		grpcServer.MustStart()
+	}
+
+	// Start invalidation of cached songs and suggestions changed by any replica
+	var songCacheInvalidator SongCacheInvalidator
+	if songCache != nil {
+		songCacheInvalidator = songCache
+	}
+	songCacheJob := NewSongCacheInvalidationJob(log, changeFeedService, songCacheInvalidator, suggestCache, &cfg.SongCache)
+	songCacheJob.MustStart()
+
+	// Start retention of soft-deleted songs
+	purgeJob := NewPurgeJob(log, songService, &cfg.PurgeConfig)
+	if cfg.PurgeConfig.GetEnabled() {
+		purgeJob.Start()
+	}
+
+	// Start retention of the change feed
+	changeFeedCleanupJob := NewChangeFeedCleanupJob(log, changeFeedService, &cfg.ChangeFeed)
+	if cfg.ChangeFeed.GetCleanupEnabled() {
+		changeFeedCleanupJob.Start()
+	}
+
+	// Start webhook deliveries
+	webhookJob := NewWebhookJob(log, webhookService, &cfg.Webhook)
+	if cfg.Webhook.GetDispatchEnabled() {
+		webhookJob.Start()
+	}
+
+	// Start relay of domain events
+	outboxJob := NewOutboxJob(log, outboxService, &cfg.Outbox)
+	if cfg.Outbox.GetRelayEnabled() {
+		outboxJob.Start()
+	}
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
 	<-stop
+
+	// Let load balancers see the failing readiness probe before draining
+	healthRegistry.SetShuttingDown()
+	if grpcServer != nil {
+		grpcServer.SetShuttingDown()
+	}
+	log.Info().Dur("delay", cfg.HealthConfig.GetShutdownDelay()).Msg("Waiting for traffic to drain")
+	time.Sleep(cfg.HealthConfig.GetShutdownDelay())
+
 	server.Stop()
-	songStorage.Close()
+	if grpcServer != nil {
+		grpcServer.Stop()
+	}
+	purgeJob.Stop()
+	changeFeedCleanupJob.Stop()
+	webhookJob.Stop()
+	outboxJob.Stop()
+	songCacheJob.Stop()
+	db.Close()
+
+	if err := shutdownTracing(context.Background()); err != nil {
+		log.Error().Err(err).Msg("Could not flush traces")
+	}
+}
+
+// NewPolicy returns the authorization policy from AUTH_POLICY_FILE, or the
+// built-in one when no file is configured.
+func NewPolicy(cfg *config.AuthConfig) (*service.Policy, error) {
+	if cfg.GetPolicyFile() == "" {
+		return service.DefaultPolicy(), nil
+	}
+
+	return service.LoadPolicy(cfg.GetPolicyFile())
+}
+
+// mustNewJWKS picks the key set source, the file source is meant for local
+// setups and tests without an identity provider.
+func mustNewJWKS(log zerolog.Logger, cfg *config.JWTConfig) *auth.JWKS {
+	switch {
+	case cfg.GetJWKSURL() != "":
+		return auth.NewURLJWKS(cfg.GetJWKSURL(), cfg.GetRefreshInterval(), &nethttp.Client{Transport: tracing.NewTransport(nil), Timeout: 10 * time.Second})
+	case cfg.GetJWKSFile() != "":
+		return auth.NewFileJWKS(cfg.GetJWKSFile(), cfg.GetRefreshInterval())
+	default:
+		log.Fatal().Msg("AUTH_JWKS_URL or AUTH_JWKS_FILE is required when JWT authentication is enabled")
+		return nil
+	}
+}
+
+func NewLogger() zerolog.Logger {
+	// Config Logger
+	// TODO: Use config
+	log := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().
+		Timestamp().
+		Str("role", tracing.ServiceName).
+		Logger()
+
+	return log.Hook(logger.TracingHook{})
 }
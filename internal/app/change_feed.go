@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/config"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/rs/zerolog"
+	"sync"
+	"time"
+)
+
+// ChangeFeedCleanupJob removes change feed events past the retention so the
+// feed does not grow with every mutation forever.
+type ChangeFeedCleanupJob struct {
+	log        zerolog.Logger
+	changeFeed service.ChangeFeedService
+	cfg        *config.ChangeFeedConfig
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+func NewChangeFeedCleanupJob(log zerolog.Logger, changeFeed service.ChangeFeedService, cfg *config.ChangeFeedConfig) *ChangeFeedCleanupJob {
+	return &ChangeFeedCleanupJob{
+		log:        log.With().Str("module", "change-feed-cleanup-job").Logger(),
+		changeFeed: changeFeed,
+		cfg:        cfg,
+	}
+}
+
+func (j *ChangeFeedCleanupJob) Start() {
+	ctx, cancel := context.WithCancel(actor.WithPrincipal(context.Background(), actor.System()))
+	j.cancel = cancel
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		j.log.Info().
+			Dur("retention", j.cfg.GetRetention()).
+			Dur("interval", j.cfg.GetCleanupInterval()).
+			Msg("Starting change feed cleanup job")
+
+		ticker := time.NewTicker(j.cfg.GetCleanupInterval())
+		defer ticker.Stop()
+
+		for {
+			j.cleanup(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (j *ChangeFeedCleanupJob) cleanup(ctx context.Context) {
+	deleted, err := j.changeFeed.Cleanup(ctx, time.Now().Add(-j.cfg.GetRetention()))
+	if err != nil {
+		if ctx.Err() == nil {
+			j.log.Error().Err(err).Msg("Could not clean up change feed")
+		}
+		return
+	}
+
+	if deleted > 0 {
+		j.log.Info().Int64("deleted", deleted).Msg("Change feed cleaned up")
+	}
+}
+
+func (j *ChangeFeedCleanupJob) Stop() {
+	if j.cancel == nil {
+		return
+	}
+
+	j.log.Info().Msg("Stopping change feed cleanup job")
+	j.cancel()
+	j.wg.Wait()
+	j.log.Info().Msg("Change feed cleanup job stopped")
+}
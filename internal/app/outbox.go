@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/config"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/eventbus"
+	"github.com/rs/zerolog"
+	"sync"
+	"time"
+)
+
+// outboxCleanupInterval is how often published events past the retention are removed.
+const outboxCleanupInterval = time.Hour
+
+// OutboxJob relays outbox events to the sinks. Every replica can run it, a
+// message is claimed by one of them at a time.
+type OutboxJob struct {
+	log           zerolog.Logger
+	outboxService service.OutboxService
+	cfg           *config.OutboxConfig
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+func NewOutboxJob(log zerolog.Logger, outboxService service.OutboxService, cfg *config.OutboxConfig) *OutboxJob {
+	return &OutboxJob{
+		log:           log.With().Str("module", "outbox-job").Logger(),
+		outboxService: outboxService,
+		cfg:           cfg,
+	}
+}
+
+func (j *OutboxJob) Start() {
+	ctx, cancel := context.WithCancel(actor.WithPrincipal(context.Background(), actor.System()))
+	j.cancel = cancel
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		j.log.Info().
+			Dur("interval", j.cfg.GetRelayInterval()).
+			Strs("sinks", j.cfg.GetSinks()).
+			Msg("Starting outbox job")
+
+		ticker := time.NewTicker(j.cfg.GetRelayInterval())
+		defer ticker.Stop()
+		cleanup := time.NewTicker(outboxCleanupInterval)
+		defer cleanup.Stop()
+
+		for {
+			relayed, err := j.outboxService.Relay(ctx)
+			if err != nil && ctx.Err() == nil {
+				j.log.Error().Err(err).Msg("Could not relay outbox events")
+			}
+
+			// A full batch means more events are due
+			if relayed == j.cfg.GetBatchSize() && ctx.Err() == nil {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-cleanup.C:
+				j.cleanup(ctx)
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (j *OutboxJob) cleanup(ctx context.Context) {
+	deleted, err := j.outboxService.Cleanup(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			j.log.Error().Err(err).Msg("Could not clean up outbox")
+		}
+		return
+	}
+
+	if deleted > 0 {
+		j.log.Info().Int64("deleted", deleted).Msg("Outbox cleaned up")
+	}
+}
+
+func (j *OutboxJob) Stop() {
+	if j.cancel == nil {
+		return
+	}
+
+	j.log.Info().Msg("Stopping outbox job")
+	j.cancel()
+	j.wg.Wait()
+	j.log.Info().Msg("Outbox job stopped")
+}
+
+// NewEventSinks returns the sinks named in OUTBOX_SINKS, "memory" selects the
+// given channel sink. Broker sinks need a client and are added in code with
+// eventbus.NewBrokerSink.
+func NewEventSinks(log zerolog.Logger, cfg *config.OutboxConfig, channel *eventbus.ChannelSink) ([]service.EventSink, error) {
+	sinks := make([]service.EventSink, 0, len(cfg.GetSinks()))
+	for _, name := range cfg.GetSinks() {
+		switch name {
+		case "log":
+			sinks = append(sinks, eventbus.NewLogSink(log))
+		case channel.Name():
+			sinks = append(sinks, channel)
+		default:
+			return nil, fmt.Errorf("unknown event sink: %s", name)
+		}
+	}
+
+	return sinks, nil
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/config"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/rs/zerolog"
+	"sync"
+	"time"
+)
+
+type PurgeJob struct {
+	log         zerolog.Logger
+	songService service.SongService
+	cfg         *config.PurgeConfig
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+func NewPurgeJob(log zerolog.Logger, songService service.SongService, cfg *config.PurgeConfig) *PurgeJob {
+	return &PurgeJob{
+		log:         log.With().Str("module", "purge-job").Logger(),
+		songService: songService,
+		cfg:         cfg,
+	}
+}
+
+func (j *PurgeJob) Start() {
+	ctx, cancel := context.WithCancel(actor.WithPrincipal(context.Background(), actor.System()))
+	j.cancel = cancel
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		j.log.Info().
+			Dur("retention", j.cfg.GetRetention()).
+			Dur("interval", j.cfg.GetInterval()).
+			Bool("dry_run", j.cfg.GetDryRun()).
+			Msg("Starting purge job")
+
+		ticker := time.NewTicker(j.cfg.GetInterval())
+		defer ticker.Stop()
+
+		for {
+			_, _ = j.RunOnce(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (j *PurgeJob) Stop() {
+	if j.cancel == nil {
+		return
+	}
+
+	j.log.Info().Msg("Stopping purge job")
+	j.cancel()
+	j.wg.Wait()
+	j.log.Info().Msg("Purge job stopped")
+}
+
+func (j *PurgeJob) RunOnce(ctx context.Context) (int64, error) {
+	cutoff := time.Now().Add(-j.cfg.GetRetention())
+	logger := j.log.With().Time("cutoff", cutoff).Logger()
+
+	count, err := j.songService.PurgeDeleted(ctx, cutoff, j.cfg.GetBatchSize(), j.cfg.GetDryRun())
+	if err != nil {
+		logger.Error().Err(err).Int64("purged", count).Msg("Purge of deleted songs failed")
+		return count, err
+	}
+
+	if j.cfg.GetDryRun() {
+		logger.Info().Int64("count", count).Msg("Dry run, deleted songs would be purged")
+	} else {
+		logger.Info().Int64("purged", count).Msg("Deleted songs purged")
+	}
+
+	return count, nil
+}
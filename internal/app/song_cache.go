@@ -0,0 +1,110 @@
+package app
+
+import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/config"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/rs/zerolog"
+	"sync"
+	"time"
+)
+
+const songCacheInvalidationBatchSize = 500
+
+type SongCacheInvalidator interface {
+	Invalidate(ctx context.Context, id string)
+}
+
+type SuggestionCache interface {
+	Purge()
+}
+
+// SongCacheInvalidationJob follows the change feed and drops cached songs
+// and suggestions changed by any replica, including reads this one cached
+// during a commit. The song cache is optional, suggestions are always cached.
+type SongCacheInvalidationJob struct {
+	log         zerolog.Logger
+	changeFeed  service.ChangeFeedService
+	invalidator SongCacheInvalidator
+	suggestions SuggestionCache
+	cfg         *config.SongCacheConfig
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+func NewSongCacheInvalidationJob(
+	log zerolog.Logger,
+	changeFeed service.ChangeFeedService,
+	invalidator SongCacheInvalidator,
+	suggestions SuggestionCache,
+	cfg *config.SongCacheConfig,
+) *SongCacheInvalidationJob {
+	return &SongCacheInvalidationJob{
+		log:         log.With().Str("module", "song-cache-invalidation-job").Logger(),
+		changeFeed:  changeFeed,
+		invalidator: invalidator,
+		suggestions: suggestions,
+		cfg:         cfg,
+	}
+}
+
+// MustStart starts following the feed after its latest change, the cache is
+// empty at startup so earlier changes do not matter.
+func (j *SongCacheInvalidationJob) MustStart() {
+	ctx, cancel := context.WithCancel(actor.WithPrincipal(context.Background(), actor.System()))
+	j.cancel = cancel
+
+	cursor, err := j.changeFeed.LastID(ctx)
+	if err != nil {
+		j.log.Fatal().Err(err).Msg("Could not read the change feed position")
+	}
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		j.log.Info().Dur("interval", j.cfg.GetInvalidationInterval()).Int64("after", cursor).Msg("Starting song cache invalidation job")
+
+		ticker := time.NewTicker(j.cfg.GetInvalidationInterval())
+		defer ticker.Stop()
+
+		for {
+			events, err := j.changeFeed.ListAfter(ctx, cursor, songCacheInvalidationBatchSize)
+			if err != nil && ctx.Err() == nil {
+				j.log.Error().Err(err).Msg("Could not read the change feed, cached songs may be stale")
+			}
+
+			for _, event := range events {
+				if j.invalidator != nil {
+					j.invalidator.Invalidate(ctx, event.SongID)
+				}
+				cursor = event.ID
+			}
+			if len(events) > 0 {
+				j.suggestions.Purge()
+			}
+
+			// A full batch means more changes are waiting
+			if len(events) == songCacheInvalidationBatchSize && ctx.Err() == nil {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (j *SongCacheInvalidationJob) Stop() {
+	if j.cancel == nil {
+		return
+	}
+
+	j.log.Info().Msg("Stopping song cache invalidation job")
+	j.cancel()
+	j.wg.Wait()
+	j.log.Info().Msg("Song cache invalidation job stopped")
+}
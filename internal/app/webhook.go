@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/config"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/rs/zerolog"
+	"sync"
+	"time"
+)
+
+// WebhookJob sends queued webhook deliveries. Every replica can run it, a
+// delivery is claimed by one of them at a time.
+type WebhookJob struct {
+	log            zerolog.Logger
+	webhookService service.WebhookService
+	cfg            *config.WebhookConfig
+	cancel         context.CancelFunc
+	wg             sync.WaitGroup
+}
+
+func NewWebhookJob(log zerolog.Logger, webhookService service.WebhookService, cfg *config.WebhookConfig) *WebhookJob {
+	return &WebhookJob{
+		log:            log.With().Str("module", "webhook-job").Logger(),
+		webhookService: webhookService,
+		cfg:            cfg,
+	}
+}
+
+func (j *WebhookJob) Start() {
+	ctx, cancel := context.WithCancel(actor.WithPrincipal(context.Background(), actor.System()))
+	j.cancel = cancel
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		j.log.Info().Dur("interval", j.cfg.GetDispatchInterval()).Msg("Starting webhook job")
+
+		ticker := time.NewTicker(j.cfg.GetDispatchInterval())
+		defer ticker.Stop()
+
+		for {
+			sent, err := j.webhookService.Dispatch(ctx)
+			if err != nil && ctx.Err() == nil {
+				j.log.Error().Err(err).Msg("Could not dispatch webhook deliveries")
+			}
+
+			// A full batch means more deliveries are due
+			if sent == j.cfg.GetBatchSize() && ctx.Err() == nil {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (j *WebhookJob) Stop() {
+	if j.cancel == nil {
+		return
+	}
+
+	j.log.Info().Msg("Stopping webhook job")
+	j.cancel()
+	j.wg.Wait()
+	j.log.Info().Msg("Webhook job stopped")
+}
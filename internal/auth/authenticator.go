@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+)
+
+var ErrMissingCredentials = errors.New("authentication required")
+
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, plaintext string) (*model.APIKey, error)
+}
+
+type BearerAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*actor.Principal, error)
+}
+
+// Credentials are what a transport read from a request, empty values mean
+// the credential was not presented.
+type Credentials struct {
+	APIKey      string
+	BearerToken string
+}
+
+// Authenticator resolves the principal of a request for every transport so
+// HTTP and gRPC callers are authenticated the same way.
+type Authenticator struct {
+	enabled       bool
+	anonymousRole actor.Role
+	apiKeys       APIKeyAuthenticator
+	bearer        BearerAuthenticator
+}
+
+// NewAuthenticator builds the authenticator, bearer may be nil when JWT
+// authentication is not configured. With authentication disabled callers get
+// anonymousRole.
+func NewAuthenticator(enabled bool, anonymousRole actor.Role, apiKeys APIKeyAuthenticator, bearer BearerAuthenticator) *Authenticator {
+	return &Authenticator{
+		enabled:       enabled,
+		anonymousRole: anonymousRole,
+		apiKeys:       apiKeys,
+		bearer:        bearer,
+	}
+}
+
+// BearerEnabled reports whether bearer tokens are accepted.
+func (a *Authenticator) BearerEnabled() bool {
+	return a.bearer != nil
+}
+
+// Authenticate returns the principal for creds. A bearer token takes
+// precedence over an API key, with authentication disabled every request runs
+// as the anonymous principal.
+func (a *Authenticator) Authenticate(ctx context.Context, creds Credentials) (*actor.Principal, error) {
+	switch {
+	case !a.enabled:
+		return actor.Anonymous(a.anonymousRole), nil
+	case a.bearer != nil && creds.BearerToken != "":
+		return a.bearer.Authenticate(ctx, creds.BearerToken)
+	case creds.APIKey != "":
+		return a.authenticateAPIKey(ctx, creds.APIKey)
+	default:
+		return nil, ErrMissingCredentials
+	}
+}
+
+func (a *Authenticator) authenticateAPIKey(ctx context.Context, plaintext string) (*actor.Principal, error) {
+	key, err := a.apiKeys.Authenticate(ctx, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]actor.Role, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		if role, ok := actor.RoleForScope(scope); ok {
+			roles = append(roles, role)
+		}
+	}
+
+	return &actor.Principal{
+		ID:     key.ID,
+		Name:   key.Name,
+		Method: actor.MethodAPIKey,
+		Roles:  roles,
+	}, nil
+}
@@ -0,0 +1,36 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"github.com/orungrau/em_song_library/internal/auth"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"testing"
+)
+
+func TestAuthenticatorDisabledGrantsAnonymousRole(t *testing.T) {
+	authenticator := auth.NewAuthenticator(false, actor.RoleViewer, nil, nil)
+
+	principal, err := authenticator.Authenticate(context.Background(), auth.Credentials{})
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+
+	if principal.Method != actor.MethodAnonymous {
+		t.Errorf("method = %q, want %q", principal.Method, actor.MethodAnonymous)
+	}
+	if !principal.HasRole(actor.RoleViewer) {
+		t.Errorf("roles = %v, want %s", principal.Roles, actor.RoleViewer)
+	}
+	if principal.HasRole(actor.RoleEditor) {
+		t.Errorf("roles = %v, anonymous callers must not write", principal.Roles)
+	}
+}
+
+func TestAuthenticatorEnabledRequiresCredentials(t *testing.T) {
+	authenticator := auth.NewAuthenticator(true, actor.RoleViewer, nil, nil)
+
+	if _, err := authenticator.Authenticate(context.Background(), auth.Credentials{}); !errors.Is(err, auth.ErrMissingCredentials) {
+		t.Fatalf("Authenticate() error = %v, want %v", err, auth.ErrMissingCredentials)
+	}
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"strings"
+	"time"
+)
+
+var ErrInvalidToken = errors.New("invalid bearer token")
+
+type JWTConfig interface {
+	GetIssuer() string
+	GetAudience() string
+	GetRolesClaim() string
+	GetNameClaim() string
+	GetRoleMapping() map[string]string
+	GetLeeway() time.Duration
+}
+
+// JWTAuthenticator validates bearer tokens against a JWKS and maps their
+// claims to a principal.
+type JWTAuthenticator struct {
+	jwks   *JWKS
+	cfg    JWTConfig
+	parser *jwt.Parser
+}
+
+func NewJWTAuthenticator(jwks *JWKS, cfg JWTConfig) *JWTAuthenticator {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithLeeway(cfg.GetLeeway()),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.GetIssuer() != "" {
+		options = append(options, jwt.WithIssuer(cfg.GetIssuer()))
+	}
+	if cfg.GetAudience() != "" {
+		options = append(options, jwt.WithAudience(cfg.GetAudience()))
+	}
+
+	return &JWTAuthenticator{
+		jwks:   jwks,
+		cfg:    cfg,
+		parser: jwt.NewParser(options...),
+	}
+}
+
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, token string) (*actor.Principal, error) {
+	claims := jwt.MapClaims{}
+
+	_, err := a.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return a.jwks.Key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	name := subject
+	if value, ok := lookupClaim(claims, a.cfg.GetNameClaim()).(string); ok && value != "" {
+		name = value
+	}
+
+	return &actor.Principal{
+		ID:     subject,
+		Name:   name,
+		Method: actor.MethodJWT,
+		Roles:  a.roles(claims),
+	}, nil
+}
+
+// roles reads the configured claim, either a list or a space separated string,
+// and translates its values through the role mapping. Values matching a role
+// name are used as is when no mapping is configured for them.
+func (a *JWTAuthenticator) roles(claims jwt.MapClaims) []actor.Role {
+	var values []string
+	switch claim := lookupClaim(claims, a.cfg.GetRolesClaim()).(type) {
+	case string:
+		values = strings.Fields(claim)
+	case []interface{}:
+		for _, value := range claim {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	mapping := a.cfg.GetRoleMapping()
+	roles := make([]actor.Role, 0, len(values))
+	for _, value := range values {
+		role := actor.Role(value)
+		if mapped, ok := mapping[value]; ok {
+			role = actor.Role(mapped)
+		}
+		if actor.IsValidRole(role) {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
+
+// lookupClaim resolves dotted paths such as realm_access.roles.
+func lookupClaim(claims jwt.MapClaims, path string) interface{} {
+	var current interface{} = map[string]interface{}(claims)
+	for _, part := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = object[part]
+	}
+
+	return current
+}
@@ -0,0 +1,209 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/orungrau/em_song_library/internal/auth"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+const (
+	testIssuer   = "https://idp.example.com"
+	testAudience = "song-library"
+	testKeyID    = "test-key"
+)
+
+type jwtConfig struct{}
+
+func (jwtConfig) GetIssuer() string        { return testIssuer }
+func (jwtConfig) GetAudience() string      { return testAudience }
+func (jwtConfig) GetRolesClaim() string    { return "realm_access.roles" }
+func (jwtConfig) GetNameClaim() string     { return "email" }
+func (jwtConfig) GetLeeway() time.Duration { return 0 }
+func (jwtConfig) GetRoleMapping() map[string]string {
+	return map[string]string{"song-editors": "editor"}
+}
+
+// writeJWKS stores the public part of key as a key set file, the way a local
+// setup without an identity provider is configured.
+func writeJWKS(t *testing.T, key *ecdsa.PrivateKey) string {
+	t.Helper()
+
+	encode := func(b []byte) string {
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	size := (key.Curve.Params().BitSize + 7) / 8
+	set := map[string]any{
+		"keys": []map[string]string{{
+			"kty": "EC",
+			"kid": testKeyID,
+			"use": "sig",
+			"crv": "P-256",
+			"x":   encode(key.X.FillBytes(make([]byte, size))),
+			"y":   encode(key.Y.FillBytes(make([]byte, size))),
+		}},
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "user-1",
+		"email": "user@example.com",
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"realm_access": map[string]any{
+			"roles": []string{"song-editors", "offline_access"},
+		},
+	}
+}
+
+func TestJWTAuthenticatorAcceptsTokenSignedByLocalKey(t *testing.T) {
+	key := newKey(t)
+	authenticator := auth.NewJWTAuthenticator(auth.NewFileJWKS(writeJWKS(t, key), time.Hour), jwtConfig{})
+
+	principal, err := authenticator.Authenticate(context.Background(), sign(t, key, testKeyID, validClaims()))
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+
+	if principal.ID != "user-1" || principal.Name != "user@example.com" {
+		t.Errorf("principal = %q (%q), want user-1 (user@example.com)", principal.ID, principal.Name)
+	}
+	if principal.Method != actor.MethodJWT {
+		t.Errorf("method = %q, want %q", principal.Method, actor.MethodJWT)
+	}
+	if !slices.Equal(principal.Roles, []actor.Role{actor.RoleEditor}) {
+		t.Errorf("roles = %v, want [%s]", principal.Roles, actor.RoleEditor)
+	}
+}
+
+func TestJWTAuthenticatorRejectsInvalidTokens(t *testing.T) {
+	key := newKey(t)
+	authenticator := auth.NewJWTAuthenticator(auth.NewFileJWKS(writeJWKS(t, key), time.Hour), jwtConfig{})
+
+	tests := []struct {
+		name  string
+		token func(t *testing.T) string
+	}{
+		{
+			name: "expired",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+				return sign(t, key, testKeyID, claims)
+			},
+		},
+		{
+			name: "without expiration",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				delete(claims, "exp")
+				return sign(t, key, testKeyID, claims)
+			},
+		},
+		{
+			name: "wrong issuer",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims["iss"] = "https://other.example.com"
+				return sign(t, key, testKeyID, claims)
+			},
+		},
+		{
+			name: "wrong audience",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims["aud"] = "other"
+				return sign(t, key, testKeyID, claims)
+			},
+		},
+		{
+			name: "without subject",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				delete(claims, "sub")
+				return sign(t, key, testKeyID, claims)
+			},
+		},
+		{
+			name: "unknown key ID",
+			token: func(t *testing.T) string {
+				return sign(t, key, "rotated-key", validClaims())
+			},
+		},
+		{
+			name: "signed by another key",
+			token: func(t *testing.T) string {
+				return sign(t, newKey(t), testKeyID, validClaims())
+			},
+		},
+		{
+			name: "unsigned",
+			token: func(t *testing.T) string {
+				token, err := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return token
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := authenticator.Authenticate(context.Background(), tt.token(t))
+			if !errors.Is(err, auth.ErrInvalidToken) {
+				t.Fatalf("Authenticate() = %v, %v, want %v", principal, err, auth.ErrInvalidToken)
+			}
+		})
+	}
+}
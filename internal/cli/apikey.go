@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func runAPIKey(ctx context.Context, env *environment, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "create":
+		flags := flag.NewFlagSet("apikey create", flag.ContinueOnError)
+		name := flags.String("name", "", "name describing the key owner")
+		scopes := flags.String("scopes", "songs:read", "comma separated list of scopes")
+		if err := flags.Parse(args[1:]); err != nil || *name == "" {
+			return errUsage
+		}
+
+		key, plaintext, err := env.apiKeyService().Create(ctx, *name, strings.Split(*scopes, ","))
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(env.out, "id: %s\nscopes: %s\nkey: %s\n", key.ID, strings.Join(key.Scopes, ","), plaintext)
+		_, _ = fmt.Fprintln(env.out, "Store the key now, it cannot be shown again.")
+		return nil
+	case "list":
+		keys, err := env.apiKeyService().List(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(env.out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tLAST USED\tREVOKED")
+		for _, key := range keys {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				key.ID, key.Name, key.Prefix, strings.Join(key.Scopes, ","),
+				formatOptionalTime(key.LastUsedAt), formatOptionalTime(key.RevokedAt))
+		}
+		return w.Flush()
+	case "revoke":
+		if len(args) != 2 {
+			return errUsage
+		}
+		if err := env.apiKeyService().Revoke(ctx, args[1]); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(env.out, "API key revoked with id: %s\n", args[1])
+		return nil
+	default:
+		return errUsage
+	}
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+
+	return t.Format(time.RFC3339)
+}
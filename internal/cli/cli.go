@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/app"
+	"github.com/orungrau/em_song_library/internal/config"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/repository/postgres"
+	"github.com/orungrau/em_song_library/internal/repository/storage/apikey"
+	"github.com/orungrau/em_song_library/internal/repository/storage/audit"
+	"github.com/orungrau/em_song_library/internal/repository/storage/changefeed"
+	"github.com/orungrau/em_song_library/internal/repository/storage/outbox"
+	"github.com/orungrau/em_song_library/internal/repository/storage/song"
+	"github.com/orungrau/em_song_library/pkg/cache"
+	"github.com/rs/zerolog"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+)
+
+var errUsage = errors.New("invalid usage")
+
+type command struct {
+	usage       string
+	description string
+	run         func(ctx context.Context, env *environment, args []string) error
+}
+
+var commands = map[string]command{
+	"serve": {
+		usage:       "serve",
+		description: "Start the HTTP server (default)",
+		run:         runServe,
+	},
+	"migrate": {
+		usage:       "migrate up | down [N] | to N | force N | status",
+		description: "Manage database migrations",
+		run:         runMigrate,
+	},
+	"import": {
+		usage:       "import [-file path]",
+		description: "Import songs from NDJSON or a JSON array (stdin by default)",
+		run:         runImport,
+	},
+	"apikey": {
+		usage:       "apikey create -name NAME [-scopes a,b] | list | revoke ID",
+		description: "Mint, list and revoke API keys",
+		run:         runAPIKey,
+	},
+	"export": {
+		usage:       "export [-file path]",
+		description: "Export songs as NDJSON (stdout by default)",
+		run:         runExport,
+	},
+	"purge": {
+		usage:       "purge [-retention duration] [-batch-size N] [-dry-run]",
+		description: "Permanently remove songs soft-deleted before the retention period",
+		run:         runPurge,
+	},
+	"seed": {
+		usage:       "seed",
+		description: "Insert a set of sample songs",
+		run:         runSeed,
+	},
+	"uniqueness": {
+		usage:       "uniqueness enable | disable | status",
+		description: "Manage the unique index on song group and title",
+		run:         runUniqueness,
+	},
+	"song": {
+		usage:       "song get ID | delete [-permanent] ID | restore ID",
+		description: "Inspect and manage a single song",
+		run:         runSong,
+	},
+}
+
+// Run executes the subcommand in args, starting the server when none is given.
+func Run(cfg *config.AppConfig, args []string) error {
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage(os.Stdout)
+		return nil
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		printUsage(os.Stderr)
+		return fmt.Errorf("unknown command: %s", name)
+	}
+
+	env := &environment{cfg: cfg, log: app.NewLogger(), out: os.Stdout, in: os.Stdin}
+	defer env.close()
+
+	// Operator commands are trusted and run as the system principal
+	ctx, cancel := signal.NotifyContext(actor.WithPrincipal(context.Background(), actor.System()), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	err := cmd.run(ctx, env, args[1:])
+	if errors.Is(err, errUsage) {
+		return fmt.Errorf("%w, usage: %s", err, cmd.usage)
+	}
+
+	return err
+}
+
+func printUsage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	_, _ = fmt.Fprintln(w, "Usage: song_library <command> [arguments]")
+	_, _ = fmt.Fprintln(w, "\nCommands:")
+	for _, name := range names {
+		_, _ = fmt.Fprintf(w, "  %-55s %s\n", commands[name].usage, commands[name].description)
+	}
+}
+
+// environment lazily connects the dependencies needed by a command.
+type environment struct {
+	cfg *config.AppConfig
+	log zerolog.Logger
+	out io.Writer
+	in  io.Reader
+
+	db      *postgres.Database
+	service service.SongService
+	apiKeys service.APIKeyService
+}
+
+func (e *environment) database() *postgres.Database {
+	if e.db == nil {
+		e.db = postgres.NewDatabase(e.log, &e.cfg.PostgresConfig)
+		e.db.MustConnect()
+	}
+
+	return e.db
+}
+
+func (e *environment) songService() service.SongService {
+	if e.service == nil {
+		policy, err := app.NewPolicy(&e.cfg.AuthConfig)
+		if err != nil {
+			e.log.Fatal().Err(err).Msg("Could not load authorization policy")
+		}
+
+		songStorage := song.NewPostgresStorage(e.log, e.database())
+		auditService := service.NewAuditService(e.log, audit.NewPostgresStorage(e.log, e.database()))
+		changeFeedService := service.NewChangeFeedService(e.log, changefeed.NewPostgresStorage(e.log, e.database()), policy)
+		// Imports queue domain events, the server relays them and sends webhooks
+		outboxService := service.NewOutboxService(e.log, outbox.NewPostgresStorage(e.log, e.database()), &e.cfg.Outbox)
+		e.service = service.NewAuthorizedSongService(
+			service.NewSongService(e.log, songStorage, e.database(), cache.NewLRU[string, []*model.Suggestion](e.cfg.SuggestConfig.GetCacheSize(), e.cfg.SuggestConfig.GetCacheTTL()), auditService, changeFeedService, service.NewSongEventHook(outboxService)),
+			policy,
+		)
+	}
+
+	return e.service
+}
+
+func (e *environment) apiKeyService() service.APIKeyService {
+	if e.apiKeys == nil {
+		e.apiKeys = service.NewAPIKeyService(e.log, apikey.NewPostgresStorage(e.log, e.database()))
+	}
+
+	return e.apiKeys
+}
+
+func (e *environment) close() {
+	if e.db != nil {
+		e.db.Close()
+	}
+}
+
+func runServe(_ context.Context, env *environment, _ []string) error {
+	app.MustRun(env.cfg)
+	return nil
+}
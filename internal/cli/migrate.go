@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+func runMigrate(ctx context.Context, env *environment, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "up":
+		return env.database().MigrateUp()
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("%w: steps must be a positive number", errUsage)
+			}
+			steps = n
+		}
+		return env.database().MigrateDown(steps)
+	case "to":
+		if len(args) < 2 {
+			return errUsage
+		}
+		version, err := strconv.ParseUint(args[1], 10, 32)
+		if err != nil {
+			return fmt.Errorf("%w: version must be a non-negative number", errUsage)
+		}
+		return env.database().MigrateTo(uint(version))
+	case "force":
+		if len(args) < 2 {
+			return errUsage
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("%w: version must be a number", errUsage)
+		}
+		return env.database().MigrateForce(version)
+	case "status":
+		status, err := env.database().MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(env.out, "version: %d\ndirty: %t\nlatest: %d\npending: %v\n",
+			status.Version, status.Dirty, status.Latest, status.Pending)
+		return nil
+	default:
+		return errUsage
+	}
+}
@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"github.com/orungrau/em_song_library/internal/app"
+)
+
+func runPurge(ctx context.Context, env *environment, args []string) error {
+	cfg := env.cfg.PurgeConfig
+
+	flags := flag.NewFlagSet("purge", flag.ContinueOnError)
+	flags.DurationVar(&cfg.Retention, "retention", cfg.Retention, "purge songs deleted longer ago than this")
+	flags.IntVar(&cfg.BatchSize, "batch-size", cfg.BatchSize, "number of songs removed per statement")
+	flags.BoolVar(&cfg.DryRun, "dry-run", cfg.DryRun, "only report how many songs would be purged")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	_, err := app.NewPurgeJob(env.log, env.songService(), &cfg).RunOnce(ctx)
+	return err
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"context"
+	"time"
+)
+
+var seedSongs = []songRecord{
+	{
+		Title:       "Bohemian Rhapsody",
+		Group:       "Queen",
+		Link:        stringPtr("https://www.youtube.com/watch?v=fJ9rUzIMcZQ"),
+		Text:        stringPtr("Is this the real life?\nIs this just fantasy?\n\nCaught in a landslide\nNo escape from reality"),
+		ReleaseDate: time.Date(1975, time.October, 31, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Title:       "Don't Stop Me Now",
+		Group:       "Queen",
+		Link:        stringPtr("https://www.youtube.com/watch?v=HgzGwKwLmgM"),
+		ReleaseDate: time.Date(1979, time.January, 26, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Title:       "Supermassive Black Hole",
+		Group:       "Muse",
+		Link:        stringPtr("https://www.youtube.com/watch?v=Xsp3_a-PMTw"),
+		Text:        stringPtr("Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?\n\nYou caught me under false pretenses\nHow long before you let me go?"),
+		ReleaseDate: time.Date(2006, time.June, 19, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Title:       "Jóga",
+		Group:       "Björk",
+		ReleaseDate: time.Date(1997, time.September, 15, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Title:       "Группа крови",
+		Group:       "Кино",
+		ReleaseDate: time.Date(1988, time.January, 4, 0, 0, 0, 0, time.UTC),
+	},
+}
+
+func runSeed(ctx context.Context, env *environment, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	return createSongs(ctx, env, seedSongs)
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
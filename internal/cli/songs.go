@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"io"
+	"os"
+	"time"
+)
+
+const exportPageSize = 500
+
+type songRecord struct {
+	ID          string     `json:"id,omitempty"`
+	Title       string     `json:"title"`
+	Text        *string    `json:"text,omitempty"`
+	Link        *string    `json:"link,omitempty"`
+	Group       string     `json:"group"`
+	ReleaseDate time.Time  `json:"release_date"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+func songRecordFromModel(song *model.Song) songRecord {
+	return songRecord{
+		ID:          *song.ID,
+		Title:       *song.Title,
+		Text:        song.Text,
+		Link:        song.Link,
+		Group:       *song.Group,
+		ReleaseDate: *song.ReleaseDate,
+		CreatedAt:   song.CreatedAt,
+		UpdatedAt:   song.UpdatedAt,
+		DeletedAt:   song.DeletedAt,
+	}
+}
+
+func (r songRecord) toModel() model.Song {
+	now := time.Now()
+
+	return model.Song{
+		Title:       &r.Title,
+		Text:        r.Text,
+		Link:        r.Link,
+		Group:       &r.Group,
+		ReleaseDate: &r.ReleaseDate,
+		CreatedAt:   &now,
+		UpdatedAt:   &now,
+	}
+}
+
+func (r songRecord) validate() error {
+	if r.Title == "" || r.Group == "" || r.ReleaseDate.IsZero() {
+		return errors.New("title, group and release_date are required")
+	}
+
+	return nil
+}
+
+func runImport(ctx context.Context, env *environment, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	file := flags.String("file", "", "file to read songs from, stdin when empty")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	in := env.in
+	if *file != "" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	records, err := decodeSongRecords(in)
+	if err != nil {
+		return err
+	}
+
+	return createSongs(ctx, env, records)
+}
+
+func createSongs(ctx context.Context, env *environment, records []songRecord) error {
+	songService := env.songService()
+
+	var created, skipped int
+	for i, record := range records {
+		if err := record.validate(); err != nil {
+			return fmt.Errorf("song #%d: %w", i+1, err)
+		}
+
+		_, err := songService.Create(ctx, record.toModel())
+		var conflict *service.SongConflictError
+		if errors.As(err, &conflict) {
+			env.log.Warn().Str("existing_id", conflict.ExistingID).Str("title", record.Title).Msg("Song already exists, skipped")
+			skipped++
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("song #%d: %w", i+1, err)
+		}
+		created++
+	}
+
+	_, _ = fmt.Fprintf(env.out, "created: %d\nskipped: %d\n", created, skipped)
+	return nil
+}
+
+// decodeSongRecords accepts both NDJSON and JSON arrays of songs.
+func decodeSongRecords(in io.Reader) ([]songRecord, error) {
+	records := make([]songRecord, 0)
+	decoder := json.NewDecoder(in)
+
+	for {
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON input: %w", err)
+		}
+
+		if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+			var batch []songRecord
+			if err := json.Unmarshal(raw, &batch); err != nil {
+				return nil, fmt.Errorf("invalid JSON input: %w", err)
+			}
+			records = append(records, batch...)
+			continue
+		}
+
+		var record songRecord
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return nil, fmt.Errorf("invalid JSON input: %w", err)
+		}
+		records = append(records, record)
+	}
+}
+
+func runExport(ctx context.Context, env *environment, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	file := flags.String("file", "", "file to write songs to, stdout when empty")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	out := env.out
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	encoder := json.NewEncoder(out)
+	songService := env.songService()
+
+	var after *model.SongCursor
+	for {
+		songs, err := songService.GetByFilters(ctx, model.SongFilter{
+			PageSize: exportPageSize,
+			After:    after,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, song := range songs {
+			if err := encoder.Encode(songRecordFromModel(song)); err != nil {
+				return err
+			}
+		}
+
+		if len(songs) < exportPageSize {
+			return nil
+		}
+		after = model.CursorAfter(songs)
+	}
+}
+
+func runSong(ctx context.Context, env *environment, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "get":
+		if len(args) != 2 {
+			return errUsage
+		}
+		song, err := env.songService().Get(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		if song == nil {
+			return fmt.Errorf("song not found: %s", args[1])
+		}
+		encoder := json.NewEncoder(env.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(songRecordFromModel(song))
+	case "delete":
+		flags := flag.NewFlagSet("song delete", flag.ContinueOnError)
+		permanent := flags.Bool("permanent", false, "remove the song instead of soft-deleting it")
+		if err := flags.Parse(args[1:]); err != nil || flags.NArg() != 1 {
+			return errUsage
+		}
+		id := flags.Arg(0)
+		if *permanent {
+			if err := env.songService().DeletePermanent(ctx, id); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(env.out, "song permanently deleted with id: %s\n", id)
+			return nil
+		}
+		if err := env.songService().Delete(ctx, id); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(env.out, "song deleted with id: %s\n", id)
+		return nil
+	case "restore":
+		if len(args) != 2 {
+			return errUsage
+		}
+		if err := env.songService().Restore(ctx, args[1]); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(env.out, "song restored with id: %s\n", args[1])
+		return nil
+	default:
+		return errUsage
+	}
+}
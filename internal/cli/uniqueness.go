@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/repository/storage/song"
+)
+
+func runUniqueness(ctx context.Context, env *environment, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	storage := song.NewPostgresStorage(env.log, env.database())
+
+	switch args[0] {
+	case "enable":
+		if err := storage.EnforceUniqueness(ctx); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(env.out, "Songs are unique by group and title")
+		return nil
+	case "disable":
+		if err := storage.RelaxUniqueness(ctx); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(env.out, "Songs may repeat a group and title")
+		return nil
+	case "status":
+		enforced, err := storage.UniquenessEnforced(ctx)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(env.out, "enforced: %t\n", enforced)
+		return nil
+	default:
+		return errUsage
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"time"
+)
+
+type AuthConfig struct {
+	Enabled       bool   `env:"AUTH_ENABLED" env-default:"false"`
+	AnonymousRole string `env:"AUTH_ANONYMOUS_ROLE" env-default:"viewer"`
+	PolicyFile    string `env:"AUTH_POLICY_FILE" env-default:""`
+	JWT           JWTConfig
+}
+
+func (a *AuthConfig) GetEnabled() bool {
+	return a.Enabled
+}
+
+func (a *AuthConfig) GetAnonymousRole() actor.Role {
+	return actor.Role(a.AnonymousRole)
+}
+
+func (a *AuthConfig) GetPolicyFile() string {
+	return a.PolicyFile
+}
+
+// Validate rejects an unknown anonymous role and JWT settings that would be
+// ignored, with authentication disabled every caller is anonymous whatever
+// token it sends.
+func (a *AuthConfig) Validate() error {
+	if !actor.IsValidRole(a.GetAnonymousRole()) {
+		return fmt.Errorf("AUTH_ANONYMOUS_ROLE must be viewer, editor or admin, got %q", a.AnonymousRole)
+	}
+	if !a.JWT.Enabled {
+		return nil
+	}
+
+	var errs []error
+	if !a.Enabled {
+		errs = append(errs, errors.New("AUTH_JWT_ENABLED requires AUTH_ENABLED=true"))
+	}
+	if a.JWT.JWKSURL == "" && a.JWT.JWKSFile == "" {
+		errs = append(errs, errors.New("AUTH_JWKS_URL or AUTH_JWKS_FILE is required when JWT authentication is enabled"))
+	}
+
+	return errors.Join(errs...)
+}
+
+type JWTConfig struct {
+	Enabled         bool              `env:"AUTH_JWT_ENABLED" env-default:"false"`
+	JWKSURL         string            `env:"AUTH_JWKS_URL" env-default:""`
+	JWKSFile        string            `env:"AUTH_JWKS_FILE" env-default:""`
+	RefreshInterval time.Duration     `env:"AUTH_JWKS_REFRESH_INTERVAL" env-default:"15m"`
+	Issuer          string            `env:"AUTH_JWT_ISSUER" env-default:""`
+	Audience        string            `env:"AUTH_JWT_AUDIENCE" env-default:""`
+	RolesClaim      string            `env:"AUTH_JWT_ROLES_CLAIM" env-default:"roles"`
+	NameClaim       string            `env:"AUTH_JWT_NAME_CLAIM" env-default:"email"`
+	RoleMapping     map[string]string `env:"AUTH_JWT_ROLE_MAPPING" env-default:""`
+	Leeway          time.Duration     `env:"AUTH_JWT_LEEWAY" env-default:"30s"`
+}
+
+func (j *JWTConfig) GetEnabled() bool {
+	return j.Enabled
+}
+
+func (j *JWTConfig) GetJWKSURL() string {
+	return j.JWKSURL
+}
+
+func (j *JWTConfig) GetJWKSFile() string {
+	return j.JWKSFile
+}
+
+func (j *JWTConfig) GetRefreshInterval() time.Duration {
+	return j.RefreshInterval
+}
+
+func (j *JWTConfig) GetIssuer() string {
+	return j.Issuer
+}
+
+func (j *JWTConfig) GetAudience() string {
+	return j.Audience
+}
+
+func (j *JWTConfig) GetRolesClaim() string {
+	return j.RolesClaim
+}
+
+func (j *JWTConfig) GetNameClaim() string {
+	return j.NameClaim
+}
+
+func (j *JWTConfig) GetRoleMapping() map[string]string {
+	return j.RoleMapping
+}
+
+func (j *JWTConfig) GetLeeway() time.Duration {
+	return j.Leeway
+}
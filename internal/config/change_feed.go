@@ -0,0 +1,43 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type ChangeFeedConfig struct {
+	PollInterval      time.Duration `env:"CHANGE_FEED_POLL_INTERVAL" env-default:"1s"`
+	HeartbeatInterval time.Duration `env:"CHANGE_FEED_HEARTBEAT_INTERVAL" env-default:"15s"`
+	CleanupEnabled    bool          `env:"CHANGE_FEED_CLEANUP_ENABLED" env-default:"true"`
+	Retention         time.Duration `env:"CHANGE_FEED_RETENTION" env-default:"720h"`
+	CleanupInterval   time.Duration `env:"CHANGE_FEED_CLEANUP_INTERVAL" env-default:"1h"`
+}
+
+func (c *ChangeFeedConfig) GetPollInterval() time.Duration {
+	return c.PollInterval
+}
+
+func (c *ChangeFeedConfig) GetHeartbeatInterval() time.Duration {
+	return c.HeartbeatInterval
+}
+
+func (c *ChangeFeedConfig) GetCleanupEnabled() bool {
+	return c.CleanupEnabled
+}
+
+func (c *ChangeFeedConfig) GetRetention() time.Duration {
+	return c.Retention
+}
+
+func (c *ChangeFeedConfig) GetCleanupInterval() time.Duration {
+	return c.CleanupInterval
+}
+
+func (c *ChangeFeedConfig) Validate() error {
+	return errors.Join(
+		requirePositive("CHANGE_FEED_POLL_INTERVAL", c.PollInterval),
+		requirePositive("CHANGE_FEED_HEARTBEAT_INTERVAL", c.HeartbeatInterval),
+		requirePositive("CHANGE_FEED_RETENTION", c.Retention),
+		requirePositive("CHANGE_FEED_CLEANUP_INTERVAL", c.CleanupInterval),
+	)
+}
@@ -0,0 +1,19 @@
+package config
+
+type CompressionConfig struct {
+	Enabled   bool     `env:"HTTP_COMPRESSION_ENABLED" env-default:"true"`
+	MinSize   int      `env:"HTTP_COMPRESSION_MIN_SIZE" env-default:"1024"`
+	Encodings []string `env:"HTTP_COMPRESSION_ENCODINGS" env-default:"zstd,br,gzip"`
+}
+
+func (c *CompressionConfig) GetEnabled() bool {
+	return c.Enabled
+}
+
+func (c *CompressionConfig) GetMinSize() int {
+	return c.MinSize
+}
+
+func (c *CompressionConfig) GetEncodings() []string {
+	return c.Encodings
+}
@@ -1,13 +1,31 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
+	"time"
 )
 
 type AppConfig struct {
-	HttpServer     HttpServerConfig
-	PostgresConfig PostgresConfig
+	HttpServer      HttpServerConfig
+	GRPCServer      GRPCServerConfig
+	PostgresConfig  PostgresConfig
+	SuggestConfig   SuggestConfig
+	PurgeConfig     PurgeConfig
+	TracingConfig   TracingConfig
+	HealthConfig    HealthConfig
+	AuthConfig      AuthConfig
+	RateLimitConfig RateLimitConfig
+	ChangeFeed      ChangeFeedConfig
+	Webhook         WebhookConfig
+	Outbox          OutboxConfig
+	SongCache       SongCacheConfig
+	Compression     CompressionConfig
+	HTTPCache       HTTPCacheConfig
+	CORS            CORSConfig
+	SecurityHeaders SecurityHeadersConfig
 }
 
 func MustLoad() *AppConfig {
@@ -20,5 +38,42 @@ func MustLoad() *AppConfig {
 		panic(err)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		panic(err)
+	}
+
 	return &cfg
 }
+
+// validator is implemented by configs with settings that would break their
+// consumers at runtime, such as intervals of tickers.
+type validator interface {
+	Validate() error
+}
+
+// Validate reports every invalid setting at once.
+func (c *AppConfig) Validate() error {
+	validators := []validator{
+		&c.PurgeConfig,
+		&c.AuthConfig,
+		&c.ChangeFeed,
+		&c.Webhook,
+		&c.Outbox,
+		&c.SongCache,
+	}
+
+	var errs []error
+	for _, v := range validators {
+		errs = append(errs, v.Validate())
+	}
+
+	return errors.Join(errs...)
+}
+
+func requirePositive(name string, value time.Duration) error {
+	if value <= 0 {
+		return fmt.Errorf("%s must be positive, got %s", name, value)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package config
+
+import "time"
+
+type CORSConfig struct {
+	AllowedOrigins   []string      `env:"CORS_ALLOWED_ORIGINS" env-default:""`
+	AllowedMethods   []string      `env:"CORS_ALLOWED_METHODS" env-default:"GET,POST,PATCH,DELETE"`
+	AllowedHeaders   []string      `env:"CORS_ALLOWED_HEADERS" env-default:"Authorization,Content-Type,X-API-Key,X-Request-ID,Accept-Language,If-Modified-Since,Last-Event-ID"`
+	ExposedHeaders   []string      `env:"CORS_EXPOSED_HEADERS" env-default:"X-Request-ID,Location,Last-Modified,Retry-After,RateLimit-Policy,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset"`
+	AllowCredentials bool          `env:"CORS_ALLOW_CREDENTIALS" env-default:"false"`
+	MaxAge           time.Duration `env:"CORS_MAX_AGE" env-default:"10m"`
+}
+
+func (c *CORSConfig) GetAllowedOrigins() []string {
+	return c.AllowedOrigins
+}
+
+func (c *CORSConfig) GetAllowedMethods() []string {
+	return c.AllowedMethods
+}
+
+func (c *CORSConfig) GetAllowedHeaders() []string {
+	return c.AllowedHeaders
+}
+
+func (c *CORSConfig) GetExposedHeaders() []string {
+	return c.ExposedHeaders
+}
+
+func (c *CORSConfig) GetAllowCredentials() bool {
+	return c.AllowCredentials
+}
+
+func (c *CORSConfig) GetMaxAge() time.Duration {
+	return c.MaxAge
+}
@@ -0,0 +1,46 @@
+package config
+
+import "time"
+
+type GRPCServerConfig struct {
+	Enabled               bool          `env:"GRPC_ENABLED" env-default:"false"`
+	Address               string        `env:"GRPC_ADDRESS" env-default:"0.0.0.0:9090"`
+	ShutdownTimeout       time.Duration `env:"GRPC_SHUTDOWN_TIMEOUT" env-default:"15s"`
+	TLSCertFile           string        `env:"GRPC_TLS_CERT_FILE" env-default:""`
+	TLSKeyFile            string        `env:"GRPC_TLS_KEY_FILE" env-default:""`
+	TLSClientCAFile       string        `env:"GRPC_TLS_CLIENT_CA_FILE" env-default:""`
+	TLSClientAuthOptional bool          `env:"GRPC_TLS_CLIENT_AUTH_OPTIONAL" env-default:"false"`
+	TLSReloadInterval     time.Duration `env:"GRPC_TLS_RELOAD_INTERVAL" env-default:"1m"`
+}
+
+func (g *GRPCServerConfig) GetEnabled() bool {
+	return g.Enabled
+}
+
+func (g *GRPCServerConfig) GetAddress() string {
+	return g.Address
+}
+
+func (g *GRPCServerConfig) GetShutdownTimeout() time.Duration {
+	return g.ShutdownTimeout
+}
+
+func (g *GRPCServerConfig) GetTLSCertFile() string {
+	return g.TLSCertFile
+}
+
+func (g *GRPCServerConfig) GetTLSKeyFile() string {
+	return g.TLSKeyFile
+}
+
+func (g *GRPCServerConfig) GetTLSClientCAFile() string {
+	return g.TLSClientCAFile
+}
+
+func (g *GRPCServerConfig) GetTLSClientAuthOptional() bool {
+	return g.TLSClientAuthOptional
+}
+
+func (g *GRPCServerConfig) GetTLSReloadInterval() time.Duration {
+	return g.TLSReloadInterval
+}
@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+type HealthConfig struct {
+	CheckTimeout  time.Duration `env:"HEALTH_CHECK_TIMEOUT" env-default:"2s"`
+	ShutdownDelay time.Duration `env:"HEALTH_SHUTDOWN_DELAY" env-default:"5s"`
+}
+
+func (h *HealthConfig) GetCheckTimeout() time.Duration {
+	return h.CheckTimeout
+}
+
+func (h *HealthConfig) GetShutdownDelay() time.Duration {
+	return h.ShutdownDelay
+}
@@ -0,0 +1,26 @@
+package config
+
+import "time"
+
+type HTTPCacheConfig struct {
+	Visibility         string        `env:"HTTP_CACHE_VISIBILITY" env-default:"private"`
+	SongMaxAge         time.Duration `env:"HTTP_CACHE_SONG_MAX_AGE" env-default:"60s"`
+	ListMaxAge         time.Duration `env:"HTTP_CACHE_LIST_MAX_AGE" env-default:"10s"`
+	LastModifiedSettle time.Duration `env:"HTTP_LAST_MODIFIED_SETTLE" env-default:"5s"`
+}
+
+func (c *HTTPCacheConfig) GetVisibility() string {
+	return c.Visibility
+}
+
+func (c *HTTPCacheConfig) GetSongMaxAge() time.Duration {
+	return c.SongMaxAge
+}
+
+func (c *HTTPCacheConfig) GetListMaxAge() time.Duration {
+	return c.ListMaxAge
+}
+
+func (c *HTTPCacheConfig) GetLastModifiedSettle() time.Duration {
+	return c.LastModifiedSettle
+}
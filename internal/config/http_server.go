@@ -1,15 +1,76 @@
 package config
 
-import "github.com/orungrau/em_song_library/pkg/transport"
+import "time"
 
 type HttpServerConfig struct {
-	Address string `env:"SERVER_ADDRESS" env-default:"0.0.0.0:8080"`
-}
-
-func NewHttpServerConfig() transport.HTTPServerConfig {
-	return &HttpServerConfig{}
+	Address               string        `env:"SERVER_ADDRESS" env-default:"0.0.0.0:8080"`
+	LegacyErrors          bool          `env:"HTTP_LEGACY_ERRORS" env-default:"false"`
+	ReadTimeout           time.Duration `env:"HTTP_READ_TIMEOUT" env-default:"30s"`
+	ReadHeaderTimeout     time.Duration `env:"HTTP_READ_HEADER_TIMEOUT" env-default:"5s"`
+	WriteTimeout          time.Duration `env:"HTTP_WRITE_TIMEOUT" env-default:"60s"`
+	IdleTimeout           time.Duration `env:"HTTP_IDLE_TIMEOUT" env-default:"120s"`
+	MaxHeaderBytes        int           `env:"HTTP_MAX_HEADER_BYTES" env-default:"1048576"`
+	ShutdownTimeout       time.Duration `env:"HTTP_SHUTDOWN_TIMEOUT" env-default:"15s"`
+	H2C                   bool          `env:"HTTP_H2C_ENABLED" env-default:"false"`
+	TLSCertFile           string        `env:"HTTP_TLS_CERT_FILE" env-default:""`
+	TLSKeyFile            string        `env:"HTTP_TLS_KEY_FILE" env-default:""`
+	TLSClientCAFile       string        `env:"HTTP_TLS_CLIENT_CA_FILE" env-default:""`
+	TLSClientAuthOptional bool          `env:"HTTP_TLS_CLIENT_AUTH_OPTIONAL" env-default:"false"`
+	TLSReloadInterval     time.Duration `env:"HTTP_TLS_RELOAD_INTERVAL" env-default:"1m"`
 }
 
 func (h *HttpServerConfig) GetAddress() string {
 	return h.Address
 }
+
+func (h *HttpServerConfig) GetLegacyErrors() bool {
+	return h.LegacyErrors
+}
+
+func (h *HttpServerConfig) GetReadTimeout() time.Duration {
+	return h.ReadTimeout
+}
+
+func (h *HttpServerConfig) GetReadHeaderTimeout() time.Duration {
+	return h.ReadHeaderTimeout
+}
+
+func (h *HttpServerConfig) GetWriteTimeout() time.Duration {
+	return h.WriteTimeout
+}
+
+func (h *HttpServerConfig) GetIdleTimeout() time.Duration {
+	return h.IdleTimeout
+}
+
+func (h *HttpServerConfig) GetMaxHeaderBytes() int {
+	return h.MaxHeaderBytes
+}
+
+func (h *HttpServerConfig) GetShutdownTimeout() time.Duration {
+	return h.ShutdownTimeout
+}
+
+func (h *HttpServerConfig) GetH2C() bool {
+	return h.H2C
+}
+
+func (h *HttpServerConfig) GetTLSCertFile() string {
+	return h.TLSCertFile
+}
+
+func (h *HttpServerConfig) GetTLSKeyFile() string {
+	return h.TLSKeyFile
+}
+
+func (h *HttpServerConfig) GetTLSClientCAFile() string {
+	return h.TLSClientCAFile
+}
+
+func (h *HttpServerConfig) GetTLSClientAuthOptional() bool {
+	return h.TLSClientAuthOptional
+}
+
+func (h *HttpServerConfig) GetTLSReloadInterval() time.Duration {
+	return h.TLSReloadInterval
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type OutboxConfig struct {
+	RelayEnabled  bool          `env:"OUTBOX_RELAY_ENABLED" env-default:"true"`
+	RelayInterval time.Duration `env:"OUTBOX_RELAY_INTERVAL" env-default:"1s"`
+	BatchSize     int           `env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+	Lease         time.Duration `env:"OUTBOX_LEASE" env-default:"30s"`
+	Backoff       time.Duration `env:"OUTBOX_BACKOFF" env-default:"1s"`
+	MaxBackoff    time.Duration `env:"OUTBOX_MAX_BACKOFF" env-default:"5m"`
+	Retention     time.Duration `env:"OUTBOX_RETENTION" env-default:"168h"`
+	Sinks         []string      `env:"OUTBOX_SINKS" env-default:"log"`
+}
+
+func (o *OutboxConfig) GetRelayEnabled() bool {
+	return o.RelayEnabled
+}
+
+func (o *OutboxConfig) GetRelayInterval() time.Duration {
+	return o.RelayInterval
+}
+
+func (o *OutboxConfig) GetBatchSize() int {
+	return o.BatchSize
+}
+
+func (o *OutboxConfig) GetLease() time.Duration {
+	return o.Lease
+}
+
+func (o *OutboxConfig) GetBackoff() time.Duration {
+	return o.Backoff
+}
+
+func (o *OutboxConfig) GetMaxBackoff() time.Duration {
+	return o.MaxBackoff
+}
+
+func (o *OutboxConfig) GetRetention() time.Duration {
+	return o.Retention
+}
+
+func (o *OutboxConfig) GetSinks() []string {
+	return o.Sinks
+}
+
+func (o *OutboxConfig) Validate() error {
+	return errors.Join(
+		requirePositive("OUTBOX_RELAY_INTERVAL", o.RelayInterval),
+		requirePositive("OUTBOX_LEASE", o.Lease),
+	)
+}
@@ -2,7 +2,7 @@ package config
 
 import (
 	"fmt"
-	"github.com/orungrau/em_song_library/internal/repository/storage/song"
+	"time"
 )
 
 type PostgresConfig struct {
@@ -13,11 +13,9 @@ type PostgresConfig struct {
 	DisableSSL bool   `env:"POSTGRES_DISABLE_SSL" env-required:"true"`
 	Database   string `env:"POSTGRES_DATABASE" env-required:"true"`
 
-	MigrationSource string `env:"POSTGRES_MIGRATION_SOURCE" env-required:"true"`
-}
-
-func NewPostgresConfig() song.PostgresStorageConfig {
-	return &PostgresConfig{}
+	MigrationSource      string        `env:"POSTGRES_MIGRATION_SOURCE" env-default:""`
+	MigrationMode        string        `env:"POSTGRES_MIGRATION_MODE" env-default:"auto"`
+	MigrationLockTimeout time.Duration `env:"POSTGRES_MIGRATION_LOCK_TIMEOUT" env-default:"1m"`
 }
 
 func (p *PostgresConfig) GetConnection() string {
@@ -39,3 +37,11 @@ func (p *PostgresConfig) GetDatabase() string {
 func (p *PostgresConfig) GetMigrationSource() string {
 	return p.MigrationSource
 }
+
+func (p *PostgresConfig) GetMigrationMode() string {
+	return p.MigrationMode
+}
+
+func (p *PostgresConfig) GetMigrationLockTimeout() time.Duration {
+	return p.MigrationLockTimeout
+}
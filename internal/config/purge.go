@@ -0,0 +1,35 @@
+package config
+
+import "time"
+
+type PurgeConfig struct {
+	Enabled   bool          `env:"PURGE_ENABLED" env-default:"false"`
+	Retention time.Duration `env:"PURGE_RETENTION" env-default:"720h"`
+	Interval  time.Duration `env:"PURGE_INTERVAL" env-default:"1h"`
+	BatchSize int           `env:"PURGE_BATCH_SIZE" env-default:"500"`
+	DryRun    bool          `env:"PURGE_DRY_RUN" env-default:"false"`
+}
+
+func (p *PurgeConfig) GetEnabled() bool {
+	return p.Enabled
+}
+
+func (p *PurgeConfig) GetRetention() time.Duration {
+	return p.Retention
+}
+
+func (p *PurgeConfig) GetInterval() time.Duration {
+	return p.Interval
+}
+
+func (p *PurgeConfig) GetBatchSize() int {
+	return p.BatchSize
+}
+
+func (p *PurgeConfig) GetDryRun() bool {
+	return p.DryRun
+}
+
+func (p *PurgeConfig) Validate() error {
+	return requirePositive("PURGE_INTERVAL", p.Interval)
+}
@@ -0,0 +1,24 @@
+package config
+
+type RateLimitConfig struct {
+	Enabled      bool              `env:"RATE_LIMIT_ENABLED" env-default:"false"`
+	IPLimit      string            `env:"RATE_LIMIT_IP" env-default:"1200/1m"`
+	DefaultLimit string            `env:"RATE_LIMIT_DEFAULT" env-default:"600/1m"`
+	RouteLimits  map[string]string `env:"RATE_LIMIT_ROUTES" env-default:"GET /songs/search:60/1m,GET /songs:120/1m"`
+}
+
+func (r *RateLimitConfig) GetEnabled() bool {
+	return r.Enabled
+}
+
+func (r *RateLimitConfig) GetIPLimit() string {
+	return r.IPLimit
+}
+
+func (r *RateLimitConfig) GetDefaultLimit() string {
+	return r.DefaultLimit
+}
+
+func (r *RateLimitConfig) GetRouteLimits() map[string]string {
+	return r.RouteLimits
+}
@@ -0,0 +1,26 @@
+package config
+
+import "time"
+
+type SecurityHeadersConfig struct {
+	HSTSMaxAge                   time.Duration `env:"SECURITY_HSTS_MAX_AGE" env-default:"8760h"`
+	HSTSIncludeSubdomains        bool          `env:"SECURITY_HSTS_INCLUDE_SUBDOMAINS" env-default:"false"`
+	ContentSecurityPolicy        string        `env:"SECURITY_CSP" env-default:"default-src 'none'; frame-ancestors 'none'"`
+	SwaggerContentSecurityPolicy string        `env:"SECURITY_SWAGGER_CSP" env-default:"default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'"`
+}
+
+func (s *SecurityHeadersConfig) GetHSTSMaxAge() time.Duration {
+	return s.HSTSMaxAge
+}
+
+func (s *SecurityHeadersConfig) GetHSTSIncludeSubdomains() bool {
+	return s.HSTSIncludeSubdomains
+}
+
+func (s *SecurityHeadersConfig) GetContentSecurityPolicy() string {
+	return s.ContentSecurityPolicy
+}
+
+func (s *SecurityHeadersConfig) GetSwaggerContentSecurityPolicy() string {
+	return s.SwaggerContentSecurityPolicy
+}
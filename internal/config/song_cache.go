@@ -0,0 +1,35 @@
+package config
+
+import "time"
+
+type SongCacheConfig struct {
+	Enabled              bool          `env:"SONG_CACHE_ENABLED" env-default:"true"`
+	Size                 int           `env:"SONG_CACHE_SIZE" env-default:"10000"`
+	ListSize             int           `env:"SONG_CACHE_LIST_SIZE" env-default:"1000"`
+	TTL                  time.Duration `env:"SONG_CACHE_TTL" env-default:"1m"`
+	InvalidationInterval time.Duration `env:"SONG_CACHE_INVALIDATION_INTERVAL" env-default:"1s"`
+}
+
+func (c *SongCacheConfig) GetEnabled() bool {
+	return c.Enabled
+}
+
+func (c *SongCacheConfig) GetSize() int {
+	return c.Size
+}
+
+func (c *SongCacheConfig) GetListSize() int {
+	return c.ListSize
+}
+
+func (c *SongCacheConfig) GetTTL() time.Duration {
+	return c.TTL
+}
+
+func (c *SongCacheConfig) GetInvalidationInterval() time.Duration {
+	return c.InvalidationInterval
+}
+
+func (c *SongCacheConfig) Validate() error {
+	return requirePositive("SONG_CACHE_INVALIDATION_INTERVAL", c.InvalidationInterval)
+}
@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+type SuggestConfig struct {
+	CacheSize int           `env:"SUGGEST_CACHE_SIZE" env-default:"1000"`
+	CacheTTL  time.Duration `env:"SUGGEST_CACHE_TTL" env-default:"30s"`
+}
+
+func (s *SuggestConfig) GetCacheSize() int {
+	return s.CacheSize
+}
+
+func (s *SuggestConfig) GetCacheTTL() time.Duration {
+	return s.CacheTTL
+}
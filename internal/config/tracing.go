@@ -0,0 +1,24 @@
+package config
+
+type TracingConfig struct {
+	Enabled     bool    `env:"TRACING_ENABLED" env-default:"false"`
+	Endpoint    string  `env:"TRACING_ENDPOINT" env-default:"localhost:4318"`
+	Insecure    bool    `env:"TRACING_INSECURE" env-default:"true"`
+	SampleRatio float64 `env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+}
+
+func (t *TracingConfig) GetEnabled() bool {
+	return t.Enabled
+}
+
+func (t *TracingConfig) GetEndpoint() string {
+	return t.Endpoint
+}
+
+func (t *TracingConfig) GetInsecure() bool {
+	return t.Insecure
+}
+
+func (t *TracingConfig) GetSampleRatio() float64 {
+	return t.SampleRatio
+}
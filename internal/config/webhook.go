@@ -0,0 +1,58 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type WebhookConfig struct {
+	DispatchEnabled  bool          `env:"WEBHOOK_DISPATCH_ENABLED" env-default:"true"`
+	DispatchInterval time.Duration `env:"WEBHOOK_DISPATCH_INTERVAL" env-default:"1s"`
+	BatchSize        int           `env:"WEBHOOK_BATCH_SIZE" env-default:"50"`
+	Timeout          time.Duration `env:"WEBHOOK_TIMEOUT" env-default:"10s"`
+	MaxAttempts      int           `env:"WEBHOOK_MAX_ATTEMPTS" env-default:"8"`
+	Backoff          time.Duration `env:"WEBHOOK_BACKOFF" env-default:"10s"`
+	MaxBackoff       time.Duration `env:"WEBHOOK_MAX_BACKOFF" env-default:"1h"`
+	// AllowPrivateNetworks lets subscriptions target internal addresses, for
+	// local development and receivers inside the deployment.
+	AllowPrivateNetworks bool `env:"WEBHOOK_ALLOW_PRIVATE_NETWORKS" env-default:"false"`
+}
+
+func (w *WebhookConfig) GetDispatchEnabled() bool {
+	return w.DispatchEnabled
+}
+
+func (w *WebhookConfig) GetDispatchInterval() time.Duration {
+	return w.DispatchInterval
+}
+
+func (w *WebhookConfig) GetBatchSize() int {
+	return w.BatchSize
+}
+
+func (w *WebhookConfig) GetTimeout() time.Duration {
+	return w.Timeout
+}
+
+func (w *WebhookConfig) GetMaxAttempts() int {
+	return w.MaxAttempts
+}
+
+func (w *WebhookConfig) GetBackoff() time.Duration {
+	return w.Backoff
+}
+
+func (w *WebhookConfig) GetMaxBackoff() time.Duration {
+	return w.MaxBackoff
+}
+
+func (w *WebhookConfig) GetAllowPrivateNetworks() bool {
+	return w.AllowPrivateNetworks
+}
+
+func (w *WebhookConfig) Validate() error {
+	return errors.Join(
+		requirePositive("WEBHOOK_DISPATCH_INTERVAL", w.DispatchInterval),
+		requirePositive("WEBHOOK_TIMEOUT", w.Timeout),
+	)
+}
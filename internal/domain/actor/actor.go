@@ -0,0 +1,113 @@
+package actor
+
+import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"slices"
+)
+
+const (
+	MethodAPIKey    = "api_key"
+	MethodJWT       = "jwt"
+	MethodAnonymous = "anonymous"
+	MethodSystem    = "system"
+)
+
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+// roleRank orders the roles, a role includes everything granted to the lower ones.
+var roleRank = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+func IsValidRole(role Role) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// RoleForScope maps API key scopes to the role they grant.
+func RoleForScope(scope string) (Role, bool) {
+	switch scope {
+	case model.ScopeSongsRead:
+		return RoleViewer, true
+	case model.ScopeSongsWrite:
+		return RoleEditor, true
+	case model.ScopeSongsAdmin:
+		return RoleAdmin, true
+	default:
+		return "", false
+	}
+}
+
+// Principal is the authenticated caller a request is executed for.
+type Principal struct {
+	ID     string
+	Name   string
+	Method string
+	Roles  []Role
+}
+
+// HasRole reports whether the principal holds role or a role above it.
+func (p *Principal) HasRole(role Role) bool {
+	return slices.ContainsFunc(p.Roles, func(granted Role) bool {
+		return roleRank[granted] >= roleRank[role]
+	})
+}
+
+// Anonymous is the principal of requests served with authentication disabled.
+// Its role is configured and read-only by default, so an open deployment
+// cannot be used to delete songs or manage keys and webhooks.
+func Anonymous(role Role) *Principal {
+	return &Principal{
+		ID:     MethodAnonymous,
+		Name:   MethodAnonymous,
+		Method: MethodAnonymous,
+		Roles:  []Role{role},
+	}
+}
+
+// System is the principal of operator commands run from the CLI.
+func System() *Principal {
+	return &Principal{
+		ID:     MethodSystem,
+		Name:   MethodSystem,
+		Method: MethodSystem,
+		Roles:  []Role{RoleAdmin},
+	}
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// Request identifies the call a principal is making, for audit records.
+type Request struct {
+	ID string
+	IP string
+}
+
+type requestKey struct{}
+
+func WithRequest(ctx context.Context, request *Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, request)
+}
+
+func RequestFromContext(ctx context.Context) (*Request, bool) {
+	request, ok := ctx.Value(requestKey{}).(*Request)
+	return request, ok && request != nil
+}
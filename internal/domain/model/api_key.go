@@ -0,0 +1,28 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	ScopeSongsRead  = "songs:read"
+	ScopeSongsWrite = "songs:write"
+	ScopeSongsAdmin = "songs:admin"
+)
+
+var Scopes = []string{ScopeSongsRead, ScopeSongsWrite, ScopeSongsAdmin}
+
+func IsValidScope(scope string) bool {
+	return slices.Contains(Scopes, scope)
+}
+
+type APIKey struct {
+	ID         string
+	Name       string
+	Prefix     string
+	Scopes     []string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
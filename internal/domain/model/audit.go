@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+const AuditEntitySong = "song"
+
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+type AuditEntry struct {
+	ID          int64
+	OccurredAt  time.Time
+	Entity      string
+	EntityID    string
+	Action      string
+	ActorID     string
+	ActorName   string
+	ActorMethod string
+	RequestID   *string
+	IP          *string
+	Before      map[string]any
+	After       map[string]any
+	Changes     map[string]AuditChange
+}
+
+type AuditFilter struct {
+	Entity   *string
+	EntityID *string
+	ActorID  *string
+	From     *time.Time
+	To       *time.Time
+	AfterID  int64
+	Limit    int
+}
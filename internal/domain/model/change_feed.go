@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// SongChangeEvent is an entry of the change feed. IDs grow in commit order,
+// so a consumer resumes from the last ID it processed.
+type SongChangeEvent struct {
+	ID         int64
+	OccurredAt time.Time
+	Change     SongChange
+	SongID     string
+	// Song is the state after the change, nil once the song is gone for good.
+	Song map[string]any
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const SongAggregate = "song"
+
+// DomainEvent is a fact about the library published to the event sinks. ID
+// stays the same when the event is published again, so consumers can drop
+// duplicates.
+type DomainEvent struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// SongEventPayload is the payload of song events, Before is nil for created
+// songs and After is nil for permanently deleted ones.
+type SongEventPayload struct {
+	Before map[string]any `json:"before"`
+	After  map[string]any `json:"after"`
+	Actor  string         `json:"actor,omitempty"`
+}
+
+// SongEventType names the event of a song change, such as song.created.
+func SongEventType(change SongChange) string {
+	return SongAggregate + "." + string(change)
+}
+
+// OutboxMessage is a claimed outbox row waiting to be relayed.
+type OutboxMessage struct {
+	ID       int64
+	Event    DomainEvent
+	Attempts int
+}
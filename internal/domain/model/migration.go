@@ -0,0 +1,9 @@
+package model
+
+type MigrationStatus struct {
+	Mode    string
+	Version uint
+	Dirty   bool
+	Latest  uint
+	Pending []uint
+}
@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 type Song struct {
 	ID          *string
@@ -15,6 +18,47 @@ type Song struct {
 	DeletedAt *time.Time
 }
 
+// NewSong is the song to create from the fields a client can set, every
+// transport maps its create request through it so none drops a field.
+func NewSong(title, group string, text, link *string, releaseDate *time.Time) Song {
+	return Song{
+		Title:       &title,
+		Group:       &group,
+		Text:        text,
+		Link:        link,
+		ReleaseDate: releaseDate,
+	}
+}
+
+type SongField string
+
+const (
+	SongFieldID          SongField = "id"
+	SongFieldTitle       SongField = "title"
+	SongFieldGroup       SongField = "group"
+	SongFieldText        SongField = "text"
+	SongFieldLink        SongField = "link"
+	SongFieldReleaseDate SongField = "release_date"
+	SongFieldCreatedAt   SongField = "created_at"
+	SongFieldUpdatedAt   SongField = "updated_at"
+)
+
+// SongFields lists every field of a song in presentation order.
+var SongFields = []SongField{
+	SongFieldID,
+	SongFieldTitle,
+	SongFieldGroup,
+	SongFieldText,
+	SongFieldLink,
+	SongFieldReleaseDate,
+	SongFieldCreatedAt,
+	SongFieldUpdatedAt,
+}
+
+func IsValidSongField(field SongField) bool {
+	return slices.Contains(SongFields, field)
+}
+
 type SongFilter struct {
 	ReleaseDateFrom *time.Time
 	ReleaseDateTo   *time.Time
@@ -24,4 +68,66 @@ type SongFilter struct {
 	Group           *string
 	Page            int
 	PageSize        int
+	// After continues the listing past the given song instead of using Page,
+	// pages stay consistent while songs are added or removed.
+	After *SongCursor
+	// IDs and Groups match songs exactly, they load a batch of songs in one query.
+	IDs    []string
+	Groups []string
+	// Fields limits the fields read from storage, all of them when empty.
+	Fields []SongField
+}
+
+// SongCursor is the position of a song in listings, which are ordered by
+// release date and then ID, both descending.
+type SongCursor struct {
+	ReleaseDate time.Time
+	ID          string
+}
+
+// CursorAfter returns the position to continue a listing after the last of
+// the songs, nil when there are none.
+func CursorAfter(songs []*Song) *SongCursor {
+	if len(songs) == 0 {
+		return nil
+	}
+
+	last := songs[len(songs)-1]
+	return &SongCursor{ReleaseDate: *last.ReleaseDate, ID: *last.ID}
+}
+
+type SongMatch struct {
+	Song  *Song
+	Score float64
+}
+
+type SuggestField string
+
+const (
+	SuggestFieldTitle SuggestField = "title"
+	SuggestFieldGroup SuggestField = "group"
+)
+
+type Suggestion struct {
+	Value     string
+	Frequency int
+}
+
+type SongChange string
+
+const (
+	SongCreated            SongChange = "created"
+	SongUpdated            SongChange = "updated"
+	SongDeleted            SongChange = "deleted"
+	SongRestored           SongChange = "restored"
+	SongDeletedPermanently SongChange = "deleted_permanently"
+)
+
+// SongMutation describes a committed change, Before is nil for created songs
+// and After is nil for permanently deleted ones.
+type SongMutation struct {
+	Change SongChange
+	SongID string
+	Before *Song
+	After  *Song
 }
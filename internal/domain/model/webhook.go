@@ -0,0 +1,83 @@
+package model
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// WebhookSongEventTypes are the events subscriptions can ask for, one per
+// kind of song change.
+var WebhookSongEventTypes = []string{
+	SongEventType(SongCreated),
+	SongEventType(SongUpdated),
+	SongEventType(SongDeleted),
+	SongEventType(SongRestored),
+	SongEventType(SongDeletedPermanently),
+}
+
+func IsValidWebhookEventType(eventType string) bool {
+	return slices.Contains(WebhookSongEventTypes, eventType)
+}
+
+type WebhookSubscription struct {
+	ID        string
+	URL       string
+	Events    []string
+	Secret    string
+	CreatedAt time.Time
+	DeletedAt *time.Time
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryDead marks deliveries that ran out of attempts.
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID string
+	EventID        string
+	EventType      string
+	Payload        json.RawMessage
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastAttemptAt  *time.Time
+	LastStatusCode *int
+	LastError      *string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+}
+
+// WebhookAttempt is a claimed delivery with what is needed to send it.
+type WebhookAttempt struct {
+	Delivery *WebhookDelivery
+	URL      string
+	Secret   string
+}
+
+// WebhookPayload is the JSON body sent to subscribers, ID stays the same
+// across retries so receivers can drop duplicates.
+type WebhookPayload struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       WebhookSongData `json:"data"`
+}
+
+type WebhookSongData struct {
+	SongID string         `json:"song_id"`
+	Song   map[string]any `json:"song"`
+}
+
+type WebhookDeliveryFilter struct {
+	SubscriptionID string
+	Status         *WebhookDeliveryStatus
+	AfterID        int64
+	Limit          int
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/rs/zerolog"
+	"reflect"
+	"time"
+)
+
+const (
+	defaultAuditPageSize = 100
+	maxAuditPageSize     = 1000
+)
+
+type AuditStorage interface {
+	Append(ctx context.Context, entry model.AuditEntry) error
+	List(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error)
+}
+
+// AuditService records song mutations and serves the audit trail. It is
+// registered as a SongMutationHook so entries commit together with the change.
+type AuditService interface {
+	SongMutationHook
+	List(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error)
+}
+
+type auditService struct {
+	log     zerolog.Logger
+	storage AuditStorage
+}
+
+func NewAuditService(log zerolog.Logger, storage AuditStorage) AuditService {
+	return &auditService{
+		log:     log.With().Str("module", "audit-service").Logger(),
+		storage: storage,
+	}
+}
+
+func (s *auditService) SongMutated(ctx context.Context, mutation model.SongMutation) error {
+	principal, ok := actor.PrincipalFromContext(ctx)
+	if !ok {
+		principal = actor.System()
+	}
+
+	entry := model.AuditEntry{
+		Entity:      model.AuditEntitySong,
+		EntityID:    mutation.SongID,
+		Action:      string(mutation.Change),
+		ActorID:     principal.ID,
+		ActorName:   principal.Name,
+		ActorMethod: principal.Method,
+		Before:      songSnapshot(mutation.Before),
+		After:       songSnapshot(mutation.After),
+	}
+	entry.Changes = diffSnapshots(entry.Before, entry.After)
+
+	if request, ok := actor.RequestFromContext(ctx); ok {
+		entry.RequestID = &request.ID
+		entry.IP = &request.IP
+	}
+
+	return s.storage.Append(ctx, entry)
+}
+
+// List returns entries ordered by ID, callers page with AfterID.
+func (s *auditService) List(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditPageSize
+	}
+	filter.Limit = min(filter.Limit, maxAuditPageSize)
+
+	return s.storage.List(ctx, filter)
+}
+
+func songSnapshot(song *model.Song) map[string]any {
+	if song == nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":           deref(song.ID),
+		"title":        deref(song.Title),
+		"text":         deref(song.Text),
+		"link":         deref(song.Link),
+		"group":        deref(song.Group),
+		"release_date": formatTime(song.ReleaseDate),
+		"created_at":   formatTime(song.CreatedAt),
+		"updated_at":   formatTime(song.UpdatedAt),
+		"deleted_at":   formatTime(song.DeletedAt),
+	}
+}
+
+func diffSnapshots(before, after map[string]any) map[string]model.AuditChange {
+	changes := make(map[string]model.AuditChange)
+
+	for field, value := range after {
+		if previous := before[field]; !reflect.DeepEqual(previous, value) {
+			changes[field] = model.AuditChange{Before: previous, After: value}
+		}
+	}
+	for field, value := range before {
+		if _, ok := after[field]; !ok && value != nil {
+			changes[field] = model.AuditChange{Before: value, After: nil}
+		}
+	}
+
+	return changes
+}
+
+func deref(value *string) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func formatTime(value *time.Time) any {
+	if value == nil {
+		return nil
+	}
+	return value.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"time"
+)
+
+// authorizedSongService checks the principal stored in the context against
+// the policy before every call, so each transport gets the same rules.
+type authorizedSongService struct {
+	service SongService
+	policy  *Policy
+}
+
+func NewAuthorizedSongService(service SongService, policy *Policy) SongService {
+	return &authorizedSongService{service: service, policy: policy}
+}
+
+func (s *authorizedSongService) authorize(ctx context.Context, action Action) error {
+	principal, _ := actor.PrincipalFromContext(ctx)
+	return s.policy.Authorize(principal, action)
+}
+
+func (s *authorizedSongService) GetByFilters(ctx context.Context, filters model.SongFilter) ([]*model.Song, error) {
+	if err := s.authorize(ctx, ActionSongRead); err != nil {
+		return nil, err
+	}
+	return s.service.GetByFilters(ctx, filters)
+}
+
+func (s *authorizedSongService) Get(ctx context.Context, id string) (*model.Song, error) {
+	if err := s.authorize(ctx, ActionSongRead); err != nil {
+		return nil, err
+	}
+	return s.service.Get(ctx, id)
+}
+
+func (s *authorizedSongService) Search(ctx context.Context, query string, limit int) ([]*model.SongMatch, error) {
+	if err := s.authorize(ctx, ActionSongRead); err != nil {
+		return nil, err
+	}
+	return s.service.Search(ctx, query, limit)
+}
+
+func (s *authorizedSongService) Suggest(ctx context.Context, field model.SuggestField, prefix string, limit int) ([]*model.Suggestion, error) {
+	if err := s.authorize(ctx, ActionSongRead); err != nil {
+		return nil, err
+	}
+	return s.service.Suggest(ctx, field, prefix, limit)
+}
+
+func (s *authorizedSongService) Create(ctx context.Context, song model.Song) (*model.Song, error) {
+	if err := s.authorize(ctx, ActionSongCreate); err != nil {
+		return nil, err
+	}
+	return s.service.Create(ctx, song)
+}
+
+func (s *authorizedSongService) Update(ctx context.Context, song model.Song) (*model.Song, error) {
+	if err := s.authorize(ctx, ActionSongUpdate); err != nil {
+		return nil, err
+	}
+	return s.service.Update(ctx, song)
+}
+
+func (s *authorizedSongService) Delete(ctx context.Context, id string) error {
+	if err := s.authorize(ctx, ActionSongDelete); err != nil {
+		return err
+	}
+	return s.service.Delete(ctx, id)
+}
+
+func (s *authorizedSongService) Restore(ctx context.Context, id string) error {
+	if err := s.authorize(ctx, ActionSongRestore); err != nil {
+		return err
+	}
+	return s.service.Restore(ctx, id)
+}
+
+func (s *authorizedSongService) DeletePermanent(ctx context.Context, id string) error {
+	if err := s.authorize(ctx, ActionSongDeletePermanent); err != nil {
+		return err
+	}
+	return s.service.DeletePermanent(ctx, id)
+}
+
+func (s *authorizedSongService) PurgeDeleted(ctx context.Context, cutoff time.Time, batchSize int, dryRun bool) (int64, error) {
+	if err := s.authorize(ctx, ActionSongPurge); err != nil {
+		return 0, err
+	}
+	return s.service.PurgeDeleted(ctx, cutoff, batchSize, dryRun)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+const (
+	defaultChangeFeedPageSize = 100
+	maxChangeFeedPageSize     = 1000
+)
+
+type ChangeFeedStorage interface {
+	Append(ctx context.Context, event model.SongChangeEvent) error
+	ListAfter(ctx context.Context, afterID int64, limit int) ([]*model.SongChangeEvent, error)
+	LastID(ctx context.Context) (int64, error)
+	LastChangedAt(ctx context.Context) (*time.Time, error)
+	// DeleteBefore removes events that occurred before the given time.
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+	// PurgedThrough returns the ID of the newest event removed, zero when
+	// none was.
+	PurgedThrough(ctx context.Context) (int64, error)
+}
+
+// ChangeFeedService numbers song mutations for consumers that follow the
+// library, such as caches and search indexers. It is registered as a
+// SongMutationHook so events commit together with the change.
+type ChangeFeedService interface {
+	SongMutationHook
+	// ListAfter returns events with an ID greater than afterID in ID order.
+	ListAfter(ctx context.Context, afterID int64, limit int) ([]*model.SongChangeEvent, error)
+	// LastID returns the ID of the latest event, zero when there is none.
+	LastID(ctx context.Context) (int64, error)
+	// LastChangedAt returns when the library last changed, nil when the feed is empty.
+	LastChangedAt(ctx context.Context) (*time.Time, error)
+	// Cleanup removes events that occurred before the cutoff.
+	Cleanup(ctx context.Context, cutoff time.Time) (int64, error)
+	// PurgedThrough returns the ID of the newest event removed by Cleanup,
+	// consumers resuming from an older ID have missed changes.
+	PurgedThrough(ctx context.Context) (int64, error)
+}
+
+type changeFeedService struct {
+	log     zerolog.Logger
+	storage ChangeFeedStorage
+	policy  *Policy
+}
+
+func NewChangeFeedService(log zerolog.Logger, storage ChangeFeedStorage, policy *Policy) ChangeFeedService {
+	return &changeFeedService{
+		log:     log.With().Str("module", "change-feed-service").Logger(),
+		storage: storage,
+		policy:  policy,
+	}
+}
+
+func (s *changeFeedService) SongMutated(ctx context.Context, mutation model.SongMutation) error {
+	return s.storage.Append(ctx, model.SongChangeEvent{
+		Change: mutation.Change,
+		SongID: mutation.SongID,
+		Song:   songSnapshot(mutation.After),
+	})
+}
+
+func (s *changeFeedService) ListAfter(ctx context.Context, afterID int64, limit int) ([]*model.SongChangeEvent, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultChangeFeedPageSize
+	}
+	limit = min(limit, maxChangeFeedPageSize)
+
+	return s.storage.ListAfter(ctx, afterID, limit)
+}
+
+func (s *changeFeedService) LastID(ctx context.Context) (int64, error) {
+	if err := s.authorize(ctx); err != nil {
+		return 0, err
+	}
+
+	return s.storage.LastID(ctx)
+}
+
+func (s *changeFeedService) LastChangedAt(ctx context.Context) (*time.Time, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.storage.LastChangedAt(ctx)
+}
+
+func (s *changeFeedService) PurgedThrough(ctx context.Context) (int64, error) {
+	if err := s.authorize(ctx); err != nil {
+		return 0, err
+	}
+
+	return s.storage.PurgedThrough(ctx)
+}
+
+func (s *changeFeedService) Cleanup(ctx context.Context, cutoff time.Time) (int64, error) {
+	principal, _ := actor.PrincipalFromContext(ctx)
+	if err := s.policy.Authorize(principal, ActionSongPurge); err != nil {
+		return 0, err
+	}
+
+	return s.storage.DeleteBefore(ctx, cutoff)
+}
+
+// authorize applies the read policy, the feed carries the songs themselves.
+func (s *changeFeedService) authorize(ctx context.Context) error {
+	principal, _ := actor.PrincipalFromContext(ctx)
+	return s.policy.Authorize(principal, ActionSongRead)
+}
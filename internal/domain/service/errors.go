@@ -0,0 +1,32 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrSongNotFound           = errors.New("song not found")
+	ErrFuzzySearchUnavailable = errors.New("fuzzy search is not available in storage")
+	ErrInvalidAPIKey          = errors.New("invalid or revoked API key")
+	ErrAPIKeyNotFound         = errors.New("API key not found or already revoked")
+	ErrWebhookNotFound        = errors.New("webhook not found or already deleted")
+	ErrWebhookDeliveryPending = errors.New("webhook delivery not found or still pending")
+)
+
+type SongConflictError struct {
+	ExistingID string
+}
+
+func (e *SongConflictError) Error() string {
+	return fmt.Sprintf("song already exists with id: %s", e.ExistingID)
+}
+
+type ForbiddenError struct {
+	Action Action
+	Reason string
+}
+
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("forbidden: %s", e.Reason)
+}
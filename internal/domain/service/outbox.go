@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+// EventPublisher records domain events. Publishing joins the transaction in
+// ctx, so events of a change that rolls back are never seen.
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...model.DomainEvent) error
+}
+
+type OutboxStorage interface {
+	Append(ctx context.Context, events []model.DomainEvent) error
+	// ClaimPending leases up to limit unpublished messages in ID order,
+	// counting an attempt for each.
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*model.OutboxMessage, error)
+	MarkPublished(ctx context.Context, ids []int64) error
+	Reschedule(ctx context.Context, id int64, reason string, delay time.Duration) error
+	// DeletePublishedBefore removes messages published before the given time.
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// EventSink receives relayed events, such as a log, an in-process channel or
+// a message broker. A batch is published again when any sink fails.
+type EventSink interface {
+	Name() string
+	Publish(ctx context.Context, events []model.DomainEvent) error
+}
+
+type OutboxConfig interface {
+	GetBatchSize() int
+	GetLease() time.Duration
+	GetBackoff() time.Duration
+	GetMaxBackoff() time.Duration
+	GetRetention() time.Duration
+}
+
+// OutboxService is the EventPublisher of the transactional outbox. Events
+// are stored with the change that caused them and relayed to the sinks by
+// Relay, at least once and in ID order within a batch.
+type OutboxService interface {
+	EventPublisher
+	// Relay publishes the pending events that are due and returns how many it claimed.
+	Relay(ctx context.Context) (int, error)
+	// Cleanup removes published events older than the retention.
+	Cleanup(ctx context.Context) (int64, error)
+}
+
+type outboxService struct {
+	log     zerolog.Logger
+	storage OutboxStorage
+	sinks   []EventSink
+	cfg     OutboxConfig
+}
+
+func NewOutboxService(log zerolog.Logger, storage OutboxStorage, cfg OutboxConfig, sinks ...EventSink) OutboxService {
+	return &outboxService{
+		log:     log.With().Str("module", "outbox-service").Logger(),
+		storage: storage,
+		sinks:   sinks,
+		cfg:     cfg,
+	}
+}
+
+func (s *outboxService) Publish(ctx context.Context, events ...model.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	return s.storage.Append(ctx, events)
+}
+
+func (s *outboxService) Relay(ctx context.Context) (int, error) {
+	messages, err := s.storage.ClaimPending(ctx, s.cfg.GetBatchSize(), s.cfg.GetLease())
+	if err != nil || len(messages) == 0 {
+		return 0, err
+	}
+
+	events := make([]model.DomainEvent, 0, len(messages))
+	ids := make([]int64, 0, len(messages))
+	for _, message := range messages {
+		events = append(events, message.Event)
+		ids = append(ids, message.ID)
+	}
+
+	if err := s.publish(ctx, events); err != nil {
+		if ctx.Err() != nil {
+			// The lease expires and the batch is relayed again
+			return len(messages), ctx.Err()
+		}
+
+		s.log.Warn().Err(err).Int("events", len(events)).Msg("Could not relay events, retrying later")
+		for _, message := range messages {
+			delay := exponentialBackoff(s.cfg.GetBackoff(), s.cfg.GetMaxBackoff(), message.Attempts)
+			if err := s.storage.Reschedule(ctx, message.ID, truncateReason(err), delay); err != nil {
+				return len(messages), err
+			}
+		}
+
+		return len(messages), nil
+	}
+
+	return len(messages), s.storage.MarkPublished(ctx, ids)
+}
+
+func (s *outboxService) publish(ctx context.Context, events []model.DomainEvent) error {
+	var errs []error
+	for _, sink := range s.sinks {
+		if err := sink.Publish(ctx, events); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *outboxService) Cleanup(ctx context.Context) (int64, error) {
+	return s.storage.DeletePublishedBefore(ctx, time.Now().Add(-s.cfg.GetRetention()))
+}
+
+type songEventHook struct {
+	publisher EventPublisher
+}
+
+// NewSongEventHook publishes a domain event for every song mutation. It is
+// registered as a SongMutationHook so the event joins the transaction of the
+// change.
+func NewSongEventHook(publisher EventPublisher) SongMutationHook {
+	return &songEventHook{publisher: publisher}
+}
+
+func (h *songEventHook) SongMutated(ctx context.Context, mutation model.SongMutation) error {
+	payload := model.SongEventPayload{
+		Before: songSnapshot(mutation.Before),
+		After:  songSnapshot(mutation.After),
+	}
+	if principal, ok := actor.PrincipalFromContext(ctx); ok {
+		payload.Actor = principal.Name
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal song event payload: %w", err)
+	}
+
+	return h.publisher.Publish(ctx, model.DomainEvent{
+		ID:            uuid.NewString(),
+		Type:          model.SongEventType(mutation.Change),
+		AggregateType: model.SongAggregate,
+		AggregateID:   mutation.SongID,
+		OccurredAt:    time.Now().UTC(),
+		Payload:       data,
+	})
+}
+
+// exponentialBackoff doubles base after every failed attempt up to maxDelay.
+func exponentialBackoff(base, maxDelay time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxDelay)
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"gopkg.in/yaml.v3"
+	"os"
+)
+
+type Action string
+
+const (
+	ActionSongRead            Action = "songs.read"
+	ActionSongCreate          Action = "songs.create"
+	ActionSongUpdate          Action = "songs.update"
+	ActionSongDelete          Action = "songs.delete"
+	ActionSongRestore         Action = "songs.restore"
+	ActionSongDeletePermanent Action = "songs.delete_permanent"
+	ActionSongPurge           Action = "songs.purge"
+)
+
+// Policy maps every action to the lowest role allowed to perform it, higher
+// roles inherit the actions of the lower ones.
+type Policy struct {
+	Actions map[Action]actor.Role `yaml:"actions"`
+}
+
+func DefaultPolicy() *Policy {
+	return &Policy{
+		Actions: map[Action]actor.Role{
+			ActionSongRead:            actor.RoleViewer,
+			ActionSongCreate:          actor.RoleEditor,
+			ActionSongUpdate:          actor.RoleEditor,
+			ActionSongDelete:          actor.RoleEditor,
+			ActionSongRestore:         actor.RoleAdmin,
+			ActionSongDeletePermanent: actor.RoleAdmin,
+			ActionSongPurge:           actor.RoleAdmin,
+		},
+	}
+}
+
+// LoadPolicy reads a YAML policy file. Actions missing from the file keep
+// their default role.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read policy file: %w", err)
+	}
+
+	var file Policy
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse policy file: %w", err)
+	}
+
+	policy := DefaultPolicy()
+	for action, role := range file.Actions {
+		if _, ok := policy.Actions[action]; !ok {
+			return nil, fmt.Errorf("policy file: unknown action %q", action)
+		}
+		if !actor.IsValidRole(role) {
+			return nil, fmt.Errorf("policy file: unknown role %q for action %q", role, action)
+		}
+		policy.Actions[action] = role
+	}
+
+	return policy, nil
+}
+
+// Authorize checks that principal may perform action.
+func (p *Policy) Authorize(principal *actor.Principal, action Action) error {
+	if principal == nil {
+		return &ForbiddenError{Action: action, Reason: "request is not authenticated"}
+	}
+
+	role, ok := p.Actions[action]
+	if !ok {
+		return &ForbiddenError{Action: action, Reason: "action is not allowed by the policy"}
+	}
+
+	if !principal.HasRole(role) {
+		return &ForbiddenError{
+			Action: action,
+			Reason: fmt.Sprintf("role %s is required to perform %s", role, action),
+		}
+	}
+
+	return nil
+}
@@ -2,39 +2,82 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/pkg/cache"
+	"github.com/orungrau/em_song_library/pkg/fuzzy"
 	"github.com/rs/zerolog"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	fallbackSearchPageSize = 500
+	fallbackSearchMinScore = 0.5
 )
 
 type SongStorage interface {
 	GetByFilters(ctx context.Context, filters model.SongFilter) ([]*model.Song, error)
 	GetById(ctx context.Context, id string, allowDeleted bool) (*model.Song, error)
+	GetForUpdate(ctx context.Context, id string) (*model.Song, error)
+	Search(ctx context.Context, query string, limit int) ([]*model.SongMatch, error)
+	Suggest(ctx context.Context, field model.SuggestField, prefix string, limit int) ([]*model.Suggestion, error)
 	Create(ctx context.Context, song model.Song) (*model.Song, error)
 	Update(ctx context.Context, song model.Song) (*model.Song, error)
 	Delete(ctx context.Context, id string) error
 	Restore(ctx context.Context, id string) error
 	DeletePermanent(ctx context.Context, id string) error
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time, batchSize int) ([]*model.Song, error)
+	CountDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// Transactor runs fn in a storage transaction carried by its context.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// SongMutationHook is notified of every song mutation inside the transaction
+// of the change, an error rolls the mutation back.
+type SongMutationHook interface {
+	SongMutated(ctx context.Context, mutation model.SongMutation) error
 }
 
 type SongService interface {
 	GetByFilters(ctx context.Context, filters model.SongFilter) ([]*model.Song, error)
 	Get(ctx context.Context, id string) (*model.Song, error)
+	Search(ctx context.Context, query string, limit int) ([]*model.SongMatch, error)
+	Suggest(ctx context.Context, field model.SuggestField, prefix string, limit int) ([]*model.Suggestion, error)
 	Create(ctx context.Context, song model.Song) (*model.Song, error)
 	Update(ctx context.Context, song model.Song) (*model.Song, error)
 	Delete(ctx context.Context, id string) error
 	Restore(ctx context.Context, id string) error
 	DeletePermanent(ctx context.Context, id string) error
+	PurgeDeleted(ctx context.Context, cutoff time.Time, batchSize int, dryRun bool) (int64, error)
 }
 
 type songService struct {
-	log     zerolog.Logger
-	storage SongStorage
+	log          zerolog.Logger
+	storage      SongStorage
+	tx           Transactor
+	hooks        []SongMutationHook
+	suggestCache *cache.LRU[string, []*model.Suggestion]
 }
 
-func NewSongService(log zerolog.Logger, storage SongStorage) SongService {
+func NewSongService(
+	log zerolog.Logger,
+	storage SongStorage,
+	tx Transactor,
+	suggestCache *cache.LRU[string, []*model.Suggestion],
+	hooks ...SongMutationHook,
+) SongService {
 	return &songService{
-		log:     log.With().Str("module", "song-service").Logger(),
-		storage: storage,
+		log:          log.With().Str("module", "song-service").Logger(),
+		storage:      storage,
+		tx:           tx,
+		hooks:        hooks,
+		suggestCache: suggestCache,
 	}
 }
 
@@ -46,22 +89,224 @@ func (s *songService) Get(ctx context.Context, id string) (*model.Song, error) {
 	return s.storage.GetById(ctx, id, false)
 }
 
+func (s *songService) Search(ctx context.Context, query string, limit int) ([]*model.SongMatch, error) {
+	matches, err := s.storage.Search(ctx, query, limit)
+	if errors.Is(err, ErrFuzzySearchUnavailable) {
+		s.log.Warn().Err(err).Msg("Falling back to in-memory fuzzy search")
+		return s.searchInMemory(ctx, query, limit)
+	}
+
+	return matches, err
+}
+
+func (s *songService) searchInMemory(ctx context.Context, query string, limit int) ([]*model.SongMatch, error) {
+	matches := make([]*model.SongMatch, 0)
+
+	var after *model.SongCursor
+	for {
+		songs, err := s.storage.GetByFilters(ctx, model.SongFilter{
+			PageSize: fallbackSearchPageSize,
+			After:    after,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, song := range songs {
+			score := max(
+				fuzzy.Similarity(query, *song.Title),
+				fuzzy.Similarity(query, *song.Group),
+			)
+			if score >= fallbackSearchMinScore {
+				matches = append(matches, &model.SongMatch{Song: song, Score: score})
+			}
+		}
+
+		if len(songs) < fallbackSearchPageSize {
+			break
+		}
+		after = model.CursorAfter(songs)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches, nil
+}
+
+func (s *songService) Suggest(ctx context.Context, field model.SuggestField, prefix string, limit int) ([]*model.Suggestion, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	key := fmt.Sprintf("%s:%d:%s", field, limit, prefix)
+
+	if suggestions, ok := s.suggestCache.Get(key); ok {
+		return suggestions, nil
+	}
+
+	suggestions, err := s.storage.Suggest(ctx, field, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	s.suggestCache.Set(key, suggestions)
+	return suggestions, nil
+}
+
 func (s *songService) Create(ctx context.Context, song model.Song) (*model.Song, error) {
-	return s.storage.Create(ctx, song)
+	var created *model.Song
+
+	err := s.withinTransaction(ctx, func(ctx context.Context) error {
+		inserted, err := s.storage.Create(ctx, song)
+		if err != nil {
+			return err
+		}
+
+		// Read the row back so hooks see the values set by the database
+		created, err = s.storage.GetById(ctx, *inserted.ID, true)
+		if err != nil {
+			return err
+		}
+		if created == nil {
+			created = inserted
+		}
+
+		return s.notify(ctx, model.SongMutation{Change: model.SongCreated, SongID: *created.ID, After: created})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
 }
 
 func (s *songService) Update(ctx context.Context, song model.Song) (*model.Song, error) {
-	return s.storage.Update(ctx, song)
+	if song.ID == nil {
+		return nil, fmt.Errorf("ID is required")
+	}
+
+	var updated *model.Song
+
+	err := s.withinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.storage.GetForUpdate(ctx, *song.ID)
+		if err != nil {
+			return err
+		}
+
+		updated, err = s.storage.Update(ctx, song)
+		if err != nil {
+			return err
+		}
+
+		return s.notify(ctx, model.SongMutation{Change: model.SongUpdated, SongID: *song.ID, Before: before, After: updated})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
 }
 
 func (s *songService) Delete(ctx context.Context, id string) error {
-	return s.storage.Delete(ctx, id)
+	return s.mutate(ctx, id, model.SongDeleted, s.storage.Delete)
 }
 
 func (s *songService) Restore(ctx context.Context, id string) error {
-	return s.storage.Restore(ctx, id)
+	return s.mutate(ctx, id, model.SongRestored, s.storage.Restore)
 }
 
 func (s *songService) DeletePermanent(ctx context.Context, id string) error {
-	return s.storage.DeletePermanent(ctx, id)
+	return s.mutate(ctx, id, model.SongDeletedPermanently, s.storage.DeletePermanent)
+}
+
+// mutate applies a change identified by the song ID and notifies the hooks
+// with the song state before and after it.
+func (s *songService) mutate(ctx context.Context, id string, change model.SongChange, apply func(ctx context.Context, id string) error) error {
+	return s.withinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.storage.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := apply(ctx, id); err != nil {
+			return err
+		}
+
+		var after *model.Song
+		if change != model.SongDeletedPermanently {
+			if after, err = s.storage.GetById(ctx, id, true); err != nil {
+				return err
+			}
+		}
+
+		return s.notify(ctx, model.SongMutation{Change: change, SongID: id, Before: before, After: after})
+	})
+}
+
+// withinTransaction runs a song mutation and drops the cached suggestions once
+// it commits, they may list a title or group the mutation changed.
+func (s *songService) withinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := s.tx.WithinTransaction(ctx, fn); err != nil {
+		return err
+	}
+
+	s.suggestCache.Purge()
+	return nil
+}
+
+func (s *songService) notify(ctx context.Context, mutation model.SongMutation) error {
+	for _, hook := range s.hooks {
+		if err := hook.SongMutated(ctx, mutation); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *songService) PurgeDeleted(ctx context.Context, cutoff time.Time, batchSize int, dryRun bool) (int64, error) {
+	if dryRun {
+		return s.storage.CountDeletedBefore(ctx, cutoff)
+	}
+
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("batch size must be positive, got %d", batchSize)
+	}
+
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		var purged int
+		err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			songs, err := s.storage.PurgeDeletedBefore(ctx, cutoff, batchSize)
+			if err != nil {
+				return err
+			}
+			purged = len(songs)
+
+			for _, song := range songs {
+				mutation := model.SongMutation{Change: model.SongDeletedPermanently, SongID: *song.ID, Before: song}
+				if err := s.notify(ctx, mutation); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+		total += int64(purged)
+
+		s.log.Debug().Int("purged", purged).Msg("Purged batch of deleted songs")
+		if purged < batchSize {
+			return total, nil
+		}
+	}
 }
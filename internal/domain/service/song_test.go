@@ -0,0 +1,70 @@
+package service_test
+
+import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/pkg/cache"
+	"github.com/rs/zerolog"
+	"testing"
+	"time"
+)
+
+// suggestTestStorage suggests the titles of the songs created so far.
+type suggestTestStorage struct {
+	service.SongStorage
+	titles []string
+}
+
+func (s *suggestTestStorage) Suggest(_ context.Context, _ model.SuggestField, _ string, _ int) ([]*model.Suggestion, error) {
+	suggestions := make([]*model.Suggestion, 0, len(s.titles))
+	for _, title := range s.titles {
+		suggestions = append(suggestions, &model.Suggestion{Value: title, Frequency: 1})
+	}
+	return suggestions, nil
+}
+
+func (s *suggestTestStorage) Create(_ context.Context, song model.Song) (*model.Song, error) {
+	id := "song-1"
+	song.ID = &id
+	s.titles = append(s.titles, *song.Title)
+	return &song, nil
+}
+
+func (s *suggestTestStorage) GetById(_ context.Context, _ string, _ bool) (*model.Song, error) {
+	return nil, nil
+}
+
+type suggestTestTransactor struct{}
+
+func (suggestTestTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestSongServiceSuggestSeesCreatedSongs(t *testing.T) {
+	releaseDate := time.Date(2006, time.June, 19, 0, 0, 0, 0, time.UTC)
+	songService := service.NewSongService(
+		zerolog.Nop(),
+		&suggestTestStorage{},
+		suggestTestTransactor{},
+		cache.NewLRU[string, []*model.Suggestion](10, time.Minute),
+	)
+	ctx := context.Background()
+
+	suggestions, err := songService.Suggest(ctx, model.SuggestFieldTitle, "", 10)
+	if err != nil || len(suggestions) != 0 {
+		t.Fatalf("Suggest() = %v, %v, want no suggestions", suggestions, err)
+	}
+
+	if _, err := songService.Create(ctx, model.NewSong("Starlight", "Muse", nil, nil, &releaseDate)); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	suggestions, err = songService.Suggest(ctx, model.SuggestFieldTitle, "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) != 1 || suggestions[0].Value != "Starlight" {
+		t.Errorf("suggestions = %v, want the created title", suggestions)
+	}
+}
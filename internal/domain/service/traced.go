@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"time"
+)
+
+// tracedSongService wraps every SongService call in a child span.
+type tracedSongService struct {
+	service SongService
+}
+
+func NewTracedSongService(service SongService) SongService {
+	return &tracedSongService{service: service}
+}
+
+func (s *tracedSongService) GetByFilters(ctx context.Context, filters model.SongFilter) ([]*model.Song, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "SongService.GetByFilters")
+	result, err := s.service.GetByFilters(ctx, filters)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedSongService) Get(ctx context.Context, id string) (*model.Song, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "SongService.Get")
+	span.SetAttributes(attribute.String("song.id", id))
+	result, err := s.service.Get(ctx, id)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedSongService) Search(ctx context.Context, query string, limit int) ([]*model.SongMatch, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "SongService.Search")
+	result, err := s.service.Search(ctx, query, limit)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedSongService) Suggest(ctx context.Context, field model.SuggestField, prefix string, limit int) ([]*model.Suggestion, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "SongService.Suggest")
+	result, err := s.service.Suggest(ctx, field, prefix, limit)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedSongService) Create(ctx context.Context, song model.Song) (*model.Song, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "SongService.Create")
+	result, err := s.service.Create(ctx, song)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedSongService) Update(ctx context.Context, song model.Song) (*model.Song, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "SongService.Update")
+	if song.ID != nil {
+		span.SetAttributes(attribute.String("song.id", *song.ID))
+	}
+	result, err := s.service.Update(ctx, song)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedSongService) Delete(ctx context.Context, id string) error {
+	ctx, span := tracing.Tracer().Start(ctx, "SongService.Delete")
+	span.SetAttributes(attribute.String("song.id", id))
+	err := s.service.Delete(ctx, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedSongService) Restore(ctx context.Context, id string) error {
+	ctx, span := tracing.Tracer().Start(ctx, "SongService.Restore")
+	span.SetAttributes(attribute.String("song.id", id))
+	err := s.service.Restore(ctx, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedSongService) DeletePermanent(ctx context.Context, id string) error {
+	ctx, span := tracing.Tracer().Start(ctx, "SongService.DeletePermanent")
+	span.SetAttributes(attribute.String("song.id", id))
+	err := s.service.DeletePermanent(ctx, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedSongService) PurgeDeleted(ctx context.Context, cutoff time.Time, batchSize int, dryRun bool) (int64, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "SongService.PurgeDeleted")
+	result, err := s.service.PurgeDeleted(ctx, cutoff, batchSize, dryRun)
+	tracing.End(span, err)
+	return result, err
+}
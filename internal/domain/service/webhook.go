@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/rs/zerolog"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	webhookSecretBytes        = 32
+	defaultWebhookPageSize    = 100
+	maxWebhookPageSize        = 1000
+	maxWebhookErrorLength     = 1024
+	webhookLeaseTimeoutFactor = 2
+)
+
+type WebhookStorage interface {
+	CreateSubscription(ctx context.Context, subscription model.WebhookSubscription) (*model.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error)
+	// DeleteSubscription also abandons the pending deliveries of the subscription.
+	DeleteSubscription(ctx context.Context, id string) error
+	// Enqueue adds a delivery of the event for every subscription to its type,
+	// once per subscription when the event is enqueued again.
+	Enqueue(ctx context.Context, eventID, eventType string, payload []byte) error
+	// ClaimDue leases up to limit due deliveries, counting an attempt for each.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*model.WebhookAttempt, error)
+	MarkDelivered(ctx context.Context, id int64, statusCode int) error
+	Reschedule(ctx context.Context, id int64, statusCode *int, reason string, delay time.Duration) error
+	MarkDead(ctx context.Context, id int64, statusCode *int, reason string) error
+	ListDeliveries(ctx context.Context, filter model.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error)
+	// Redeliver queues a delivery that is not pending again with fresh attempts.
+	Redeliver(ctx context.Context, subscriptionID string, id int64) error
+}
+
+// WebhookSender posts a delivery and returns the status code of the response.
+// A response outside 2xx is reported as an error together with its code.
+type WebhookSender interface {
+	Send(ctx context.Context, attempt *model.WebhookAttempt) (int, error)
+}
+
+type WebhookConfig interface {
+	GetBatchSize() int
+	GetTimeout() time.Duration
+	GetMaxAttempts() int
+	GetBackoff() time.Duration
+	GetMaxBackoff() time.Duration
+}
+
+// WebhookService manages subscriptions and delivers song events to them. It
+// is an EventSink of the outbox, which hands it every committed event, and
+// sends the queued deliveries with retries of their own per subscription.
+type WebhookService interface {
+	EventSink
+	// CreateSubscription generates a secret when none is given.
+	CreateSubscription(ctx context.Context, url string, events []string, secret string) (*model.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, filter model.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error)
+	Redeliver(ctx context.Context, subscriptionID string, id int64) error
+	// Dispatch sends the deliveries that are due and returns how many it tried.
+	Dispatch(ctx context.Context) (int, error)
+}
+
+type webhookService struct {
+	log     zerolog.Logger
+	storage WebhookStorage
+	sender  WebhookSender
+	cfg     WebhookConfig
+}
+
+func NewWebhookService(log zerolog.Logger, storage WebhookStorage, sender WebhookSender, cfg WebhookConfig) WebhookService {
+	return &webhookService{
+		log:     log.With().Str("module", "webhook-service").Logger(),
+		storage: storage,
+		sender:  sender,
+		cfg:     cfg,
+	}
+}
+
+func (s *webhookService) Name() string {
+	return "webhooks"
+}
+
+// Publish queues deliveries of the song events. Events relayed again are
+// queued once, so subscribers see a single delivery per event.
+func (s *webhookService) Publish(ctx context.Context, events []model.DomainEvent) error {
+	for _, event := range events {
+		if !model.IsValidWebhookEventType(event.Type) {
+			continue
+		}
+
+		var songEvent model.SongEventPayload
+		if err := json.Unmarshal(event.Payload, &songEvent); err != nil {
+			// Retrying cannot fix the payload, the event is skipped
+			s.log.Error().Err(err).Str("event_id", event.ID).Msg("Could not read song event for webhooks")
+			continue
+		}
+
+		payload, err := json.Marshal(model.WebhookPayload{
+			ID:         event.ID,
+			Type:       event.Type,
+			OccurredAt: event.OccurredAt,
+			Data: model.WebhookSongData{
+				SongID: event.AggregateID,
+				Song:   songEvent.After,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("marshal webhook payload: %w", err)
+		}
+
+		if err := s.storage.Enqueue(ctx, event.ID, event.Type, payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *webhookService) CreateSubscription(ctx context.Context, url string, events []string, secret string) (*model.WebhookSubscription, error) {
+	for _, event := range events {
+		if !model.IsValidWebhookEventType(event) {
+			return nil, fmt.Errorf("unknown event type: %s", event)
+		}
+	}
+
+	if secret == "" {
+		generated, err := randomHex(webhookSecretBytes)
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	return s.storage.CreateSubscription(ctx, model.WebhookSubscription{
+		URL:    url,
+		Events: events,
+		Secret: secret,
+	})
+}
+
+func (s *webhookService) ListSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	return s.storage.ListSubscriptions(ctx)
+}
+
+func (s *webhookService) DeleteSubscription(ctx context.Context, id string) error {
+	return s.storage.DeleteSubscription(ctx, id)
+}
+
+// ListDeliveries returns deliveries ordered by ID, callers page with AfterID.
+func (s *webhookService) ListDeliveries(ctx context.Context, filter model.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultWebhookPageSize
+	}
+	filter.Limit = min(filter.Limit, maxWebhookPageSize)
+
+	return s.storage.ListDeliveries(ctx, filter)
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, subscriptionID string, id int64) error {
+	return s.storage.Redeliver(ctx, subscriptionID, id)
+}
+
+func (s *webhookService) Dispatch(ctx context.Context) (int, error) {
+	// The lease outlives a send, so a crashed replica's deliveries are retried
+	attempts, err := s.storage.ClaimDue(ctx, s.cfg.GetBatchSize(), webhookLeaseTimeoutFactor*s.cfg.GetTimeout())
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, attempt := range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.deliver(ctx, attempt)
+		}()
+	}
+	wg.Wait()
+
+	return len(attempts), nil
+}
+
+func (s *webhookService) deliver(ctx context.Context, attempt *model.WebhookAttempt) {
+	delivery := attempt.Delivery
+	logger := s.log.With().
+		Int64("delivery_id", delivery.ID).
+		Str("subscription_id", delivery.SubscriptionID).
+		Str("event_type", delivery.EventType).
+		Int("attempt", delivery.Attempts).
+		Logger()
+
+	sendCtx, cancel := context.WithTimeout(ctx, s.cfg.GetTimeout())
+	statusCode, err := s.sender.Send(sendCtx, attempt)
+	cancel()
+
+	var code *int
+	if statusCode != 0 {
+		code = &statusCode
+	}
+
+	switch {
+	case err == nil:
+		err = s.storage.MarkDelivered(ctx, delivery.ID, statusCode)
+	case delivery.Attempts >= s.cfg.GetMaxAttempts():
+		logger.Warn().Err(err).Msg("Webhook delivery failed for the last time, moving it to dead letters")
+		err = s.storage.MarkDead(ctx, delivery.ID, code, truncateReason(err))
+	default:
+		delay := exponentialBackoff(s.cfg.GetBackoff(), s.cfg.GetMaxBackoff(), delivery.Attempts)
+		logger.Info().Err(err).Dur("retry_in", delay).Msg("Webhook delivery failed, retrying later")
+		err = s.storage.Reschedule(ctx, delivery.ID, code, truncateReason(err), delay)
+	}
+
+	if err != nil && !errors.Is(err, context.Canceled) {
+		// The lease expires and the delivery is attempted again
+		logger.Error().Err(err).Msg("Could not record webhook delivery result")
+	}
+}
+
+func truncateReason(err error) string {
+	reason := err.Error()
+	if len(reason) > maxWebhookErrorLength {
+		// Cutting may split a rune, which Postgres rejects in text columns
+		reason = strings.ToValidUTF8(reason[:maxWebhookErrorLength], "")
+	}
+
+	return reason
+}
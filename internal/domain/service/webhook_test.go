@@ -0,0 +1,163 @@
+package service_test
+
+import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/webhook"
+	"github.com/rs/zerolog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type webhookTestConfig struct{}
+
+func (webhookTestConfig) GetBatchSize() int            { return 10 }
+func (webhookTestConfig) GetTimeout() time.Duration    { return time.Second }
+func (webhookTestConfig) GetMaxAttempts() int          { return 3 }
+func (webhookTestConfig) GetBackoff() time.Duration    { return 10 * time.Second }
+func (webhookTestConfig) GetMaxBackoff() time.Duration { return time.Hour }
+
+// webhookTestStorage keeps deliveries in memory, every delivery is due and
+// claiming one counts an attempt like the Postgres storage does.
+type webhookTestStorage struct {
+	service.WebhookStorage
+
+	mu         sync.Mutex
+	url        string
+	deliveries []*model.WebhookDelivery
+	delays     []time.Duration
+}
+
+func (s *webhookTestStorage) Enqueue(_ context.Context, eventID, eventType string, payload []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deliveries = append(s.deliveries, &model.WebhookDelivery{
+		ID:        int64(len(s.deliveries) + 1),
+		EventID:   eventID,
+		EventType: eventType,
+		Payload:   payload,
+		Status:    model.WebhookDeliveryPending,
+	})
+	return nil
+}
+
+func (s *webhookTestStorage) ClaimDue(_ context.Context, limit int, _ time.Duration) ([]*model.WebhookAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempts := make([]*model.WebhookAttempt, 0)
+	for _, delivery := range s.deliveries {
+		if delivery.Status != model.WebhookDeliveryPending || len(attempts) == limit {
+			continue
+		}
+		delivery.Attempts++
+		claimed := *delivery
+		attempts = append(attempts, &model.WebhookAttempt{Delivery: &claimed, URL: s.url, Secret: "secret"})
+	}
+
+	return attempts, nil
+}
+
+func (s *webhookTestStorage) MarkDelivered(_ context.Context, id int64, statusCode int) error {
+	return s.record(id, model.WebhookDeliveryDelivered, &statusCode)
+}
+
+func (s *webhookTestStorage) Reschedule(_ context.Context, id int64, statusCode *int, _ string, delay time.Duration) error {
+	s.mu.Lock()
+	s.delays = append(s.delays, delay)
+	s.mu.Unlock()
+
+	return s.record(id, model.WebhookDeliveryPending, statusCode)
+}
+
+func (s *webhookTestStorage) MarkDead(_ context.Context, id int64, statusCode *int, _ string) error {
+	return s.record(id, model.WebhookDeliveryDead, statusCode)
+}
+
+func (s *webhookTestStorage) record(id int64, status model.WebhookDeliveryStatus, statusCode *int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delivery := s.deliveries[id-1]
+	delivery.Status = status
+	delivery.LastStatusCode = statusCode
+	return nil
+}
+
+func newWebhookTestService(t *testing.T, handler http.HandlerFunc) (service.WebhookService, *webhookTestStorage) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	storage := &webhookTestStorage{url: server.URL}
+	webhookService := service.NewWebhookService(zerolog.Nop(), storage, webhook.NewHTTPSender(true), webhookTestConfig{})
+
+	event := model.DomainEvent{
+		ID:            "0b7f3c2e-8d0e-4a57-9f57-0c7d8f0d9a11",
+		Type:          model.SongEventType(model.SongCreated),
+		AggregateType: model.SongAggregate,
+		AggregateID:   "5f1c1f52-1a5b-4b6c-9d55-4d7c3f1b2a10",
+		OccurredAt:    time.Now().UTC(),
+		Payload:       []byte(`{"before":null,"after":{"title":"Supermassive Black Hole"}}`),
+	}
+	if err := webhookService.Publish(context.Background(), []model.DomainEvent{event}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	return webhookService, storage
+}
+
+func TestWebhookDispatchDelivers(t *testing.T) {
+	webhookService, storage := newWebhookTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	sent, err := webhookService.Dispatch(context.Background())
+	if err != nil || sent != 1 {
+		t.Fatalf("Dispatch() = %d, %v, want 1, nil", sent, err)
+	}
+
+	delivery := storage.deliveries[0]
+	if delivery.Status != model.WebhookDeliveryDelivered || *delivery.LastStatusCode != http.StatusNoContent {
+		t.Fatalf("delivery status = %s, want %s with 204", delivery.Status, model.WebhookDeliveryDelivered)
+	}
+	if delivery.EventType != "song.created" || delivery.EventID != "0b7f3c2e-8d0e-4a57-9f57-0c7d8f0d9a11" {
+		t.Fatalf("delivery of %s %s, want the published song.created event", delivery.EventType, delivery.EventID)
+	}
+}
+
+func TestWebhookDispatchRetriesThenDeadLetters(t *testing.T) {
+	webhookService, storage := newWebhookTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		if _, err := webhookService.Dispatch(context.Background()); err != nil {
+			t.Fatalf("Dispatch() attempt %d error = %v", attempt, err)
+		}
+	}
+
+	delivery := storage.deliveries[0]
+	if delivery.Status != model.WebhookDeliveryDead {
+		t.Fatalf("delivery status = %s, want %s", delivery.Status, model.WebhookDeliveryDead)
+	}
+	if *delivery.LastStatusCode != http.StatusInternalServerError {
+		t.Fatalf("last status code = %d, want 500", *delivery.LastStatusCode)
+	}
+
+	// Retries back off exponentially from WEBHOOK_BACKOFF
+	want := []time.Duration{10 * time.Second, 20 * time.Second}
+	if len(storage.delays) != len(want) || storage.delays[0] != want[0] || storage.delays[1] != want[1] {
+		t.Fatalf("retry delays = %v, want %v", storage.delays, want)
+	}
+
+	if sent, _ := webhookService.Dispatch(context.Background()); sent != 0 {
+		t.Fatalf("Dispatch() after dead letter sent %d deliveries, want 0", sent)
+	}
+}
@@ -0,0 +1,68 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+)
+
+// Message is what a broker receives. Topic maps to a NATS subject or a Kafka
+// topic, and Key to the Kafka partition key, so events of a song keep their
+// order.
+type Message struct {
+	Topic   string
+	Key     string
+	Value   []byte
+	Headers map[string]string
+}
+
+// Producer sends messages to a broker. Adapters of NATS or Kafka clients
+// implement it, Produce returns once the broker acknowledged the message.
+type Producer interface {
+	Produce(ctx context.Context, message Message) error
+}
+
+// BrokerSink publishes events as JSON messages on topics named after the
+// event type with a prefix, such as library.song.created.
+type BrokerSink struct {
+	name        string
+	producer    Producer
+	topicPrefix string
+}
+
+func NewBrokerSink(name string, producer Producer, topicPrefix string) *BrokerSink {
+	return &BrokerSink{
+		name:        name,
+		producer:    producer,
+		topicPrefix: topicPrefix,
+	}
+}
+
+func (s *BrokerSink) Name() string {
+	return s.name
+}
+
+func (s *BrokerSink) Publish(ctx context.Context, events []model.DomainEvent) error {
+	for _, event := range events {
+		value, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("marshal event %s: %w", event.ID, err)
+		}
+
+		err = s.producer.Produce(ctx, Message{
+			Topic: s.topicPrefix + event.Type,
+			Key:   event.AggregateID,
+			Value: value,
+			Headers: map[string]string{
+				"event-id":   event.ID,
+				"event-type": event.Type,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("produce event %s: %w", event.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package eventbus
+
+import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// ChannelSink hands events to in-process subscribers. Publishing waits for
+// every subscriber to take the event, so a slow subscriber holds up the
+// relay rather than losing events.
+type ChannelSink struct {
+	mu          sync.RWMutex
+	subscribers map[*subscription]struct{}
+}
+
+type subscription struct {
+	// mu is held while sending, so the channel is never closed mid-send
+	mu   sync.Mutex
+	ch   chan model.DomainEvent
+	done chan struct{}
+}
+
+func NewChannelSink() *ChannelSink {
+	return &ChannelSink{subscribers: make(map[*subscription]struct{})}
+}
+
+func (s *ChannelSink) Name() string {
+	return "memory"
+}
+
+// Subscribe returns a channel of the events relayed from now on and a
+// function that ends the subscription and closes the channel. The function
+// does not wait for the subscriber to drain the channel.
+func (s *ChannelSink) Subscribe(buffer int) (<-chan model.DomainEvent, func()) {
+	sub := &subscription{
+		ch:   make(chan model.DomainEvent, buffer),
+		done: make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subscribers, sub)
+			s.mu.Unlock()
+
+			// Releases a Publish blocked on this subscriber before closing
+			close(sub.done)
+			sub.mu.Lock()
+			close(sub.ch)
+			sub.mu.Unlock()
+		})
+	}
+}
+
+func (s *ChannelSink) Publish(ctx context.Context, events []model.DomainEvent) error {
+	s.mu.RLock()
+	subscribers := slices.Collect(maps.Keys(s.subscribers))
+	s.mu.RUnlock()
+
+	for _, event := range events {
+		for _, sub := range subscribers {
+			if err := sub.send(ctx, event); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (sub *subscription) send(ctx context.Context, event model.DomainEvent) error {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	select {
+	case <-sub.done:
+		return nil
+	default:
+	}
+
+	select {
+	case sub.ch <- event:
+		return nil
+	case <-sub.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
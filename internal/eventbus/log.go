@@ -0,0 +1,35 @@
+package eventbus
+
+import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/rs/zerolog"
+)
+
+// LogSink writes every event to the log, useful in development and as an
+// audit of what was relayed.
+type LogSink struct {
+	log zerolog.Logger
+}
+
+func NewLogSink(log zerolog.Logger) *LogSink {
+	return &LogSink{log: log.With().Str("module", "event-log-sink").Logger()}
+}
+
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+func (s *LogSink) Publish(_ context.Context, events []model.DomainEvent) error {
+	for _, event := range events {
+		s.log.Info().
+			Str("event_id", event.ID).
+			Str("event_type", event.Type).
+			Str("aggregate_id", event.AggregateID).
+			Time("occurred_at", event.OccurredAt).
+			RawJSON("payload", event.Payload).
+			Msg("Domain event")
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type checkerFunc struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func NewChecker(name string, check func(ctx context.Context) error) Checker {
+	return &checkerFunc{name: name, check: check}
+}
+
+func (c *checkerFunc) Name() string {
+	return c.name
+}
+
+func (c *checkerFunc) Check(ctx context.Context) error {
+	return c.check(ctx)
+}
+
+type CheckResult struct {
+	Name     string
+	Status   string
+	Error    string
+	Optional bool
+	Duration time.Duration
+}
+
+type Report struct {
+	Status string
+	Checks []CheckResult
+}
+
+type registration struct {
+	checker  Checker
+	optional bool
+}
+
+// Registry runs the registered checkers to decide whether the service can
+// take traffic. Failing optional checkers are reported without affecting it.
+type Registry struct {
+	mu           sync.RWMutex
+	checkers     []registration
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+func (r *Registry) Register(checker Checker, optional bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checkers = append(r.checkers, registration{checker: checker, optional: optional})
+}
+
+// SetShuttingDown makes the service report not ready so that load balancers
+// stop routing new requests before the server stops.
+func (r *Registry) SetShuttingDown() {
+	r.shuttingDown.Store(true)
+}
+
+func (r *Registry) Ready(ctx context.Context) Report {
+	r.mu.RLock()
+	checkers := append([]registration(nil), r.checkers...)
+	r.mu.RUnlock()
+
+	report := Report{
+		Status: StatusUp,
+		Checks: make([]CheckResult, len(checkers)),
+	}
+
+	var wg sync.WaitGroup
+	for i, registered := range checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = r.run(ctx, registered)
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status == StatusDown && !result.Optional {
+			report.Status = StatusDown
+		}
+	}
+
+	if r.shuttingDown.Load() {
+		report.Status = StatusDown
+		report.Checks = append(report.Checks, CheckResult{
+			Name:   "shutdown",
+			Status: StatusDown,
+			Error:  "service is shutting down",
+		})
+	}
+
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, registered registration) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := registered.checker.Check(ctx)
+
+	result := CheckResult{
+		Name:     registered.checker.Name(),
+		Status:   StatusUp,
+		Optional: registered.optional,
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
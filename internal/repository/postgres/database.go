@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/orungrau/em_song_library/internal/tracing"
+	"github.com/rs/zerolog"
+	"sync"
+	"time"
+)
+
+type Config interface {
+	GetConnection() string
+	GetDatabase() string
+	GetMigrationSource() string
+	GetMigrationMode() string
+	GetMigrationLockTimeout() time.Duration
+}
+
+type Database struct {
+	pool *pgxpool.Pool
+	log  zerolog.Logger
+	cfg  Config
+
+	latestOnce sync.Once
+	latest     uint
+	latestErr  error
+}
+
+func NewDatabase(log zerolog.Logger, cfg Config) *Database {
+	return &Database{
+		cfg: cfg,
+		log: log.With().Str("module", "postgres").Logger(),
+	}
+}
+
+func (d *Database) MustConnect() {
+	ctx := context.Background()
+
+	poolConfig, err := pgxpool.ParseConfig(d.cfg.GetConnection())
+	if err != nil {
+		d.log.Fatal().Err(err).Msg("Failed to parse PostgreSQL connection string")
+		return
+	}
+	poolConfig.ConnConfig.Tracer = tracing.QueryTracer{}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		d.log.Fatal().Err(err).Msg("Failed to create PostgreSQL connection pool")
+		return
+	}
+
+	if err = pool.Ping(ctx); err != nil {
+		d.log.Fatal().Err(err).Msg("Failed to connect to PostgreSQL database")
+		return
+	}
+
+	d.log.Info().Msg("Successfully connected to PostgreSQL database")
+	d.pool = pool
+}
+
+func (d *Database) Pool() *pgxpool.Pool {
+	return d.pool
+}
+
+func (d *Database) Ping(ctx context.Context) error {
+	return d.pool.Ping(ctx)
+}
+
+func (d *Database) Close() {
+	if d.pool != nil {
+		d.pool.Close()
+		d.log.Info().Msg("PostgreSQL connection pool closed")
+	}
+}
@@ -0,0 +1,283 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	migratepg "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/migrations"
+	"io/fs"
+	"time"
+)
+
+const (
+	MigrationModeAuto     = "auto"
+	MigrationModeCheck    = "check"
+	MigrationModeDisabled = "disabled"
+
+	// migrationLockKey guards migrations across replicas sharing the database.
+	migrationLockKey = int64(0x736f6e675f6d6967)
+
+	migrationLockRetry = 500 * time.Millisecond
+)
+
+// MustMigrate brings the schema in line with the configured migration mode.
+func (d *Database) MustMigrate() {
+	log := d.log.With().Str("mode", d.cfg.GetMigrationMode()).Logger()
+
+	switch d.cfg.GetMigrationMode() {
+	case MigrationModeAuto:
+		if err := d.MigrateUp(); err != nil {
+			log.Fatal().Err(err).Msg("Could not apply migrations, use `migrate force N` to fix a dirty database")
+		}
+	case MigrationModeCheck:
+		status, err := d.MigrationStatus(context.Background())
+		if err != nil {
+			log.Fatal().Err(err).Msg("Could not check migrations")
+		}
+		if status.Dirty || len(status.Pending) > 0 {
+			log.Fatal().
+				Uint("version", status.Version).
+				Bool("dirty", status.Dirty).
+				Uints("pending", status.Pending).
+				Msg("Database schema is not up to date")
+		}
+		log.Info().Uint("version", status.Version).Msg("Database schema is up to date")
+	case MigrationModeDisabled:
+		log.Info().Msg("Migrations are disabled")
+	default:
+		log.Fatal().Msg("Unknown migration mode")
+	}
+}
+
+func (d *Database) MigrateUp() error {
+	return d.migrate("up", func(m *migrate.Migrate) error {
+		return m.Up()
+	})
+}
+
+func (d *Database) MigrateDown(steps int) error {
+	return d.migrate("down", func(m *migrate.Migrate) error {
+		return m.Steps(-steps)
+	})
+}
+
+func (d *Database) MigrateTo(version uint) error {
+	return d.migrate("to", func(m *migrate.Migrate) error {
+		return m.Migrate(version)
+	})
+}
+
+func (d *Database) MigrateForce(version int) error {
+	return d.migrate("force", func(m *migrate.Migrate) error {
+		return m.Force(version)
+	})
+}
+
+func (d *Database) MigrationStatus(ctx context.Context) (*model.MigrationStatus, error) {
+	status := &model.MigrationStatus{
+		Mode:    d.cfg.GetMigrationMode(),
+		Pending: make([]uint, 0),
+	}
+
+	err := d.withMigrate(ctx, false, func(m *migrate.Migrate) error {
+		version, dirty, err := m.Version()
+		if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+			return err
+		}
+		status.Version = version
+		status.Dirty = dirty
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := d.sourceVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, version := range versions {
+		status.Latest = version
+		if version > status.Version {
+			status.Pending = append(status.Pending, version)
+		}
+	}
+
+	return status, nil
+}
+
+// CheckSchema fails when the database is dirty or behind the migrations known
+// to this binary. It reads the version table directly, so it is cheap enough
+// for readiness probes.
+func (d *Database) CheckSchema(ctx context.Context) error {
+	d.latestOnce.Do(func() {
+		versions, err := d.sourceVersions()
+		if err != nil {
+			d.latestErr = err
+			return
+		}
+		if len(versions) > 0 {
+			d.latest = versions[len(versions)-1]
+		}
+	})
+	if d.latestErr != nil {
+		return d.latestErr
+	}
+
+	var version int64
+	var dirty bool
+	err := d.pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil {
+		return fmt.Errorf("could not read schema version: %w", err)
+	}
+
+	if dirty {
+		return fmt.Errorf("schema version %d is dirty", version)
+	}
+	if uint(version) < d.latest {
+		return fmt.Errorf("schema version %d is behind latest migration %d", version, d.latest)
+	}
+
+	return nil
+}
+
+func (d *Database) migrate(direction string, run func(m *migrate.Migrate) error) error {
+	return d.withMigrate(context.Background(), true, func(m *migrate.Migrate) error {
+		if err := run(m); err != nil {
+			if errors.Is(err, migrate.ErrNoChange) {
+				d.log.Info().Str("direction", direction).Msg("No migrations to apply")
+				return nil
+			}
+			return err
+		}
+
+		d.log.Info().Str("direction", direction).Msg("Migrations applied successfully")
+		return nil
+	})
+}
+
+// withMigrate runs fn against a migrate instance, holding the migration
+// advisory lock when exclusive so concurrent replicas apply changes one by one.
+func (d *Database) withMigrate(ctx context.Context, exclusive bool, run func(m *migrate.Migrate) error) error {
+	if exclusive {
+		unlock, err := d.lockMigrations(ctx)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	driver, err := migratepg.WithInstance(stdlib.OpenDBFromPool(d.pool), &migratepg.Config{})
+	if err != nil {
+		return fmt.Errorf("could not create postgres driver: %w", err)
+	}
+
+	src, err := d.openSource()
+	if err != nil {
+		_ = driver.Close()
+		return err
+	}
+
+	m, err := migrate.NewWithInstance("migrations", src, d.cfg.GetDatabase(), driver)
+	if err != nil {
+		_ = src.Close()
+		_ = driver.Close()
+		return fmt.Errorf("could not create migrate instance: %w", err)
+	}
+
+	defer func() {
+		srcErr, dbErr := m.Close()
+		if err := errors.Join(srcErr, dbErr); err != nil {
+			d.log.Error().Err(err).Msg("Error closing migrations connection")
+		}
+	}()
+
+	return run(m)
+}
+
+func (d *Database) lockMigrations(ctx context.Context) (func(), error) {
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.GetMigrationLockTimeout())
+	defer cancel()
+
+	conn, err := d.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not acquire connection for migration lock: %w", err)
+	}
+
+	ticker := time.NewTicker(migrationLockRetry)
+	defer ticker.Stop()
+
+	for {
+		var locked bool
+		err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, migrationLockKey).Scan(&locked)
+		if err != nil {
+			conn.Release()
+			return nil, fmt.Errorf("could not take migration lock: %w", err)
+		}
+		if locked {
+			break
+		}
+
+		d.log.Info().Msg("Waiting for another instance to finish migrations")
+		select {
+		case <-ctx.Done():
+			conn.Release()
+			return nil, fmt.Errorf("timed out waiting for migration lock: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+
+	return func() {
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+			d.log.Error().Err(err).Msg("Could not release migration lock")
+		}
+		conn.Release()
+	}, nil
+}
+
+// openSource uses the configured migration source, falling back to the
+// migrations embedded into the binary.
+func (d *Database) openSource() (source.Driver, error) {
+	if url := d.cfg.GetMigrationSource(); url != "" {
+		src, err := source.Open(url)
+		if err != nil {
+			return nil, fmt.Errorf("could not open migration source: %w", err)
+		}
+		return src, nil
+	}
+
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("could not open embedded migrations: %w", err)
+	}
+	return src, nil
+}
+
+func (d *Database) sourceVersions() ([]uint, error) {
+	src, err := d.openSource()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	versions := make([]uint, 0)
+
+	version, err := src.First()
+	for err == nil {
+		versions = append(versions, version)
+		version, err = src.Next(version)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("could not list migrations: %w", err)
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,134 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/repository/postgres"
+	"github.com/rs/zerolog"
+)
+
+type apiKeyPostgresStorage struct {
+	pool *pgxpool.Pool
+	log  zerolog.Logger
+}
+
+func NewPostgresStorage(log zerolog.Logger, db *postgres.Database) service.APIKeyStorage {
+	return &apiKeyPostgresStorage{
+		pool: db.Pool(),
+		log:  log.With().Str("module", "api-key-postgres-storage").Logger(),
+	}
+}
+
+func (s *apiKeyPostgresStorage) Create(ctx context.Context, key model.APIKey, hash []byte) (*model.APIKey, error) {
+	query := `
+		INSERT INTO api_keys (name, prefix, key_hash, scopes)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err := s.pool.QueryRow(ctx, query, key.Name, key.Prefix, hash, key.Scopes).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (s *apiKeyPostgresStorage) GetByHash(ctx context.Context, hash []byte) (*model.APIKey, error) {
+	query := `
+		SELECT id, name, prefix, scopes, created_at, last_used_at, revoked_at
+		FROM api_keys
+		WHERE key_hash = $1`
+
+	var key model.APIKey
+	err := s.pool.QueryRow(ctx, query, hash).Scan(
+		&key.ID,
+		&key.Name,
+		&key.Prefix,
+		&key.Scopes,
+		&key.CreatedAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (s *apiKeyPostgresStorage) List(ctx context.Context) ([]*model.APIKey, error) {
+	query := `
+		SELECT id, name, prefix, scopes, created_at, last_used_at, revoked_at
+		FROM api_keys
+		ORDER BY created_at DESC`
+
+	rows, err := s.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]*model.APIKey, 0)
+	for rows.Next() {
+		var key model.APIKey
+		err := rows.Scan(
+			&key.ID,
+			&key.Name,
+			&key.Prefix,
+			&key.Scopes,
+			&key.CreatedAt,
+			&key.LastUsedAt,
+			&key.RevokedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, &key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (s *apiKeyPostgresStorage) Revoke(ctx context.Context, id string) error {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND revoked_at IS NULL
+	`
+
+	result, err := s.pool.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return service.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// TouchLastUsed records the use at most once a minute per key to avoid a
+// write on every authenticated request.
+func (s *apiKeyPostgresStorage) TouchLastUsed(ctx context.Context, id string) error {
+	query := `
+		UPDATE api_keys
+		SET last_used_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - INTERVAL '1 minute')
+	`
+
+	_, err := s.pool.Exec(ctx, query, id)
+	return err
+}
@@ -0,0 +1,163 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/repository/postgres"
+	"github.com/rs/zerolog"
+)
+
+type auditPostgresStorage struct {
+	db  *postgres.Database
+	log zerolog.Logger
+}
+
+func NewPostgresStorage(log zerolog.Logger, db *postgres.Database) service.AuditStorage {
+	return &auditPostgresStorage{
+		db:  db,
+		log: log.With().Str("module", "audit-postgres-storage").Logger(),
+	}
+}
+
+// Append writes the entry with the transaction of the audited change when
+// one is open in ctx.
+func (s *auditPostgresStorage) Append(ctx context.Context, entry model.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (
+			entity, entity_id, action, actor_id, actor_name, actor_method,
+			request_id, ip, before, after, changes
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	before, err := marshalNullable(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := marshalNullable(entry.After)
+	if err != nil {
+		return err
+	}
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("marshal audit changes: %w", err)
+	}
+
+	_, err = s.db.Conn(ctx).Exec(
+		ctx,
+		query,
+		entry.Entity,
+		entry.EntityID,
+		entry.Action,
+		entry.ActorID,
+		entry.ActorName,
+		entry.ActorMethod,
+		entry.RequestID,
+		entry.IP,
+		before,
+		after,
+		changes,
+	)
+
+	return err
+}
+
+func (s *auditPostgresStorage) List(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	query := `
+		SELECT id, occurred_at, entity, entity_id, action, actor_id, actor_name, actor_method,
+		       request_id, ip, before, after, changes
+		FROM audit_log
+		WHERE id > $1`
+	args := []interface{}{filter.AfterID}
+	argIndex := 2
+
+	if filter.Entity != nil {
+		query += fmt.Sprintf(" AND entity = $%d", argIndex)
+		args = append(args, *filter.Entity)
+		argIndex++
+	}
+	if filter.EntityID != nil {
+		query += fmt.Sprintf(" AND entity_id = $%d", argIndex)
+		args = append(args, *filter.EntityID)
+		argIndex++
+	}
+	if filter.ActorID != nil {
+		query += fmt.Sprintf(" AND actor_id = $%d", argIndex)
+		args = append(args, *filter.ActorID)
+		argIndex++
+	}
+	if filter.From != nil {
+		query += fmt.Sprintf(" AND occurred_at >= $%d", argIndex)
+		args = append(args, filter.From.UTC())
+		argIndex++
+	}
+	if filter.To != nil {
+		query += fmt.Sprintf(" AND occurred_at < $%d", argIndex)
+		args = append(args, filter.To.UTC())
+		argIndex++
+	}
+
+	query += fmt.Sprintf(" ORDER BY id LIMIT $%d", argIndex)
+	args = append(args, filter.Limit)
+
+	rows, err := s.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]*model.AuditEntry, 0)
+	for rows.Next() {
+		var (
+			entry   model.AuditEntry
+			changes []byte
+		)
+		err := rows.Scan(
+			&entry.ID,
+			&entry.OccurredAt,
+			&entry.Entity,
+			&entry.EntityID,
+			&entry.Action,
+			&entry.ActorID,
+			&entry.ActorName,
+			&entry.ActorMethod,
+			&entry.RequestID,
+			&entry.IP,
+			&entry.Before,
+			&entry.After,
+			&changes,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+			return nil, fmt.Errorf("unmarshal audit changes: %w", err)
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// marshalNullable keeps missing snapshots as SQL NULL rather than JSON null.
+func marshalNullable(snapshot map[string]any) ([]byte, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("marshal audit snapshot: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,126 @@
+package changefeed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/repository/postgres"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+// appendLockID is the advisory lock serializing writers of the feed. Holding
+// it until commit means an ID is never visible before a lower one.
+const appendLockID = 7_300_044
+
+type changeFeedPostgresStorage struct {
+	db  *postgres.Database
+	log zerolog.Logger
+}
+
+func NewPostgresStorage(log zerolog.Logger, db *postgres.Database) service.ChangeFeedStorage {
+	return &changeFeedPostgresStorage{
+		db:  db,
+		log: log.With().Str("module", "change-feed-postgres-storage").Logger(),
+	}
+}
+
+// Append writes the event with the transaction of the change when one is
+// open in ctx, the advisory lock is then held until that transaction ends.
+func (s *changeFeedPostgresStorage) Append(ctx context.Context, event model.SongChangeEvent) error {
+	var song []byte
+	if event.Song != nil {
+		data, err := json.Marshal(event.Song)
+		if err != nil {
+			return fmt.Errorf("marshal change feed song: %w", err)
+		}
+		song = data
+	}
+
+	conn := s.db.Conn(ctx)
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, appendLockID); err != nil {
+		return err
+	}
+
+	_, err := conn.Exec(
+		ctx,
+		`INSERT INTO song_changes (change, song_id, song) VALUES ($1, $2, $3)`,
+		event.Change,
+		event.SongID,
+		song,
+	)
+
+	return err
+}
+
+func (s *changeFeedPostgresStorage) ListAfter(ctx context.Context, afterID int64, limit int) ([]*model.SongChangeEvent, error) {
+	query := `
+		SELECT id, occurred_at, change, song_id, song
+		FROM song_changes
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2`
+
+	rows, err := s.db.Conn(ctx).Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*model.SongChangeEvent, 0)
+	for rows.Next() {
+		var event model.SongChangeEvent
+		if err := rows.Scan(&event.ID, &event.OccurredAt, &event.Change, &event.SongID, &event.Song); err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (s *changeFeedPostgresStorage) LastID(ctx context.Context) (int64, error) {
+	var id int64
+	err := s.db.Conn(ctx).QueryRow(ctx, `SELECT COALESCE(MAX(id), 0) FROM song_changes`).Scan(&id)
+
+	return id, err
+}
+
+func (s *changeFeedPostgresStorage) LastChangedAt(ctx context.Context) (*time.Time, error) {
+	var occurredAt *time.Time
+	err := s.db.Conn(ctx).QueryRow(ctx, `SELECT MAX(occurred_at) FROM song_changes`).Scan(&occurredAt)
+
+	return occurredAt, err
+}
+
+// DeleteBefore records the newest removed ID in the same statement, so the
+// mark never lags behind the deleted events.
+func (s *changeFeedPostgresStorage) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		WITH deleted AS (
+			DELETE FROM song_changes WHERE occurred_at < $1 RETURNING id
+		), marked AS (
+			UPDATE song_changes_retention
+			SET purged_through = GREATEST(purged_through, (SELECT MAX(id) FROM deleted))
+			WHERE EXISTS (SELECT 1 FROM deleted)
+		)
+		SELECT COUNT(*) FROM deleted`
+
+	var deleted int64
+	err := s.db.Conn(ctx).QueryRow(ctx, query, before).Scan(&deleted)
+
+	return deleted, err
+}
+
+func (s *changeFeedPostgresStorage) PurgedThrough(ctx context.Context) (int64, error) {
+	var id int64
+	err := s.db.Conn(ctx).QueryRow(ctx, `SELECT purged_through FROM song_changes_retention`).Scan(&id)
+
+	return id, err
+}
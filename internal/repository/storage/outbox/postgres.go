@@ -0,0 +1,143 @@
+package outbox
+
+import (
+	"cmp"
+	"context"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/repository/postgres"
+	"github.com/rs/zerolog"
+	"slices"
+	"time"
+)
+
+type outboxPostgresStorage struct {
+	db  *postgres.Database
+	log zerolog.Logger
+}
+
+func NewPostgresStorage(log zerolog.Logger, db *postgres.Database) service.OutboxStorage {
+	return &outboxPostgresStorage{
+		db:  db,
+		log: log.With().Str("module", "outbox-postgres-storage").Logger(),
+	}
+}
+
+// Append writes the events with the transaction of the change when one is
+// open in ctx.
+func (s *outboxPostgresStorage) Append(ctx context.Context, events []model.DomainEvent) error {
+	query := `
+		INSERT INTO outbox (event_id, event_type, aggregate_type, aggregate_id, payload, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	conn := s.db.Conn(ctx)
+	for _, event := range events {
+		_, err := conn.Exec(
+			ctx,
+			query,
+			event.ID,
+			event.Type,
+			event.AggregateType,
+			event.AggregateID,
+			[]byte(event.Payload),
+			event.OccurredAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ClaimPending skips rows locked by other replicas, so each message is
+// relayed by one of them, and pushes the next attempt past the lease.
+func (s *outboxPostgresStorage) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*model.OutboxMessage, error) {
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM outbox
+			WHERE published_at IS NULL AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE outbox o
+		SET attempts = o.attempts + 1,
+		    next_attempt_at = CURRENT_TIMESTAMP + $2::bigint * INTERVAL '1 millisecond'
+		FROM due
+		WHERE o.id = due.id
+		RETURNING o.id, o.event_id, o.event_type, o.aggregate_type, o.aggregate_id, o.payload, o.occurred_at, o.attempts`
+
+	rows, err := s.db.Conn(ctx).Query(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]*model.OutboxMessage, 0)
+	for rows.Next() {
+		var message model.OutboxMessage
+		err := rows.Scan(
+			&message.ID,
+			&message.Event.ID,
+			&message.Event.Type,
+			&message.Event.AggregateType,
+			&message.Event.AggregateID,
+			&message.Event.Payload,
+			&message.Event.OccurredAt,
+			&message.Attempts,
+		)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, &message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// UPDATE ... RETURNING does not keep the order of the CTE
+	slices.SortFunc(messages, func(a, b *model.OutboxMessage) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return messages, nil
+}
+
+func (s *outboxPostgresStorage) MarkPublished(ctx context.Context, ids []int64) error {
+	query := `
+		UPDATE outbox
+		SET published_at = CURRENT_TIMESTAMP, last_error = NULL
+		WHERE id = ANY($1)`
+
+	_, err := s.db.Conn(ctx).Exec(ctx, query, ids)
+
+	return err
+}
+
+func (s *outboxPostgresStorage) Reschedule(ctx context.Context, id int64, reason string, delay time.Duration) error {
+	query := `
+		UPDATE outbox
+		SET next_attempt_at = CURRENT_TIMESTAMP + $3::bigint * INTERVAL '1 millisecond',
+		    last_error = $2
+		WHERE id = $1`
+
+	_, err := s.db.Conn(ctx).Exec(ctx, query, id, reason, delay.Milliseconds())
+
+	return err
+}
+
+func (s *outboxPostgresStorage) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM outbox
+		WHERE published_at IS NOT NULL AND published_at < $1`
+
+	tag, err := s.db.Conn(ctx).Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,195 @@
+package song
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/repository/postgres"
+	"github.com/orungrau/em_song_library/pkg/cache"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	cachedSongsName = "songs"
+	cachedListsName = "song_lists"
+
+	// maxCachedPageSize bounds the lists worth caching, larger pages come
+	// from scans such as exports and would fill the cache with lyrics.
+	maxCachedPageSize = 100
+)
+
+type CacheObserver interface {
+	ObserveCache(cache string, hit bool)
+}
+
+// CachedStorage is a read-through cache of songs by ID and of filtered
+// lists. Reads inside a transaction go to the storage, so mutations see the
+// rows they lock.
+//
+// Mutations passing through it invalidate the affected entries once their
+// transaction commits, so a read cannot cache the old row after that.
+// Invalidate is also called for every change of the change feed, which
+// covers the other replicas and reads that were in flight during the commit.
+type CachedStorage struct {
+	service.SongStorage
+	songs    cache.Cache[*model.Song]
+	lists    cache.Cache[[]*model.Song]
+	observer CacheObserver
+	// generation is part of list keys, bumping it drops every cached list
+	// without knowing which lists hold a song.
+	generation atomic.Uint64
+}
+
+func NewCachedStorage(
+	storage service.SongStorage,
+	songs cache.Cache[*model.Song],
+	lists cache.Cache[[]*model.Song],
+	observer CacheObserver,
+) *CachedStorage {
+	return &CachedStorage{
+		SongStorage: storage,
+		songs:       songs,
+		lists:       lists,
+		observer:    observer,
+	}
+}
+
+// Invalidate drops the cached song with the given ID and every cached list.
+func (s *CachedStorage) Invalidate(ctx context.Context, id string) {
+	s.songs.Delete(ctx, songKey(id, false), songKey(id, true))
+	s.generation.Add(1)
+}
+
+// invalidateAfterCommit defers Invalidate until the mutation is visible to
+// other readers, a rolled back mutation changed nothing.
+func (s *CachedStorage) invalidateAfterCommit(ctx context.Context, id string) {
+	postgres.AfterCommit(ctx, func() {
+		s.Invalidate(ctx, id)
+	})
+}
+
+func (s *CachedStorage) GetById(ctx context.Context, id string, allowDeleted bool) (*model.Song, error) {
+	if postgres.InTransaction(ctx) {
+		return s.SongStorage.GetById(ctx, id, allowDeleted)
+	}
+
+	key := songKey(id, allowDeleted)
+	if song, ok := s.songs.Get(ctx, key); ok {
+		s.observer.ObserveCache(cachedSongsName, true)
+		return cloneSong(song), nil
+	}
+	s.observer.ObserveCache(cachedSongsName, false)
+
+	song, err := s.SongStorage.GetById(ctx, id, allowDeleted)
+	// Missing songs are not cached, a create on another replica would go unseen
+	if err != nil || song == nil {
+		return song, err
+	}
+
+	s.songs.Set(ctx, key, cloneSong(song))
+	return song, nil
+}
+
+func (s *CachedStorage) GetByFilters(ctx context.Context, filters model.SongFilter) ([]*model.Song, error) {
+	if postgres.InTransaction(ctx) || filters.PageSize <= 0 || filters.PageSize > maxCachedPageSize {
+		return s.SongStorage.GetByFilters(ctx, filters)
+	}
+
+	key, err := s.listKey(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	if songs, ok := s.lists.Get(ctx, key); ok {
+		s.observer.ObserveCache(cachedListsName, true)
+		return cloneSongs(songs), nil
+	}
+	s.observer.ObserveCache(cachedListsName, false)
+
+	songs, err := s.SongStorage.GetByFilters(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lists.Set(ctx, key, cloneSongs(songs))
+	return songs, nil
+}
+
+func (s *CachedStorage) Create(ctx context.Context, song model.Song) (*model.Song, error) {
+	created, err := s.SongStorage.Create(ctx, song)
+	if err == nil && created.ID != nil {
+		s.invalidateAfterCommit(ctx, *created.ID)
+	}
+
+	return created, err
+}
+
+func (s *CachedStorage) Update(ctx context.Context, song model.Song) (*model.Song, error) {
+	updated, err := s.SongStorage.Update(ctx, song)
+	if song.ID != nil {
+		s.invalidateAfterCommit(ctx, *song.ID)
+	}
+
+	return updated, err
+}
+
+func (s *CachedStorage) Delete(ctx context.Context, id string) error {
+	err := s.SongStorage.Delete(ctx, id)
+	s.invalidateAfterCommit(ctx, id)
+	return err
+}
+
+func (s *CachedStorage) Restore(ctx context.Context, id string) error {
+	err := s.SongStorage.Restore(ctx, id)
+	s.invalidateAfterCommit(ctx, id)
+	return err
+}
+
+func (s *CachedStorage) DeletePermanent(ctx context.Context, id string) error {
+	err := s.SongStorage.DeletePermanent(ctx, id)
+	s.invalidateAfterCommit(ctx, id)
+	return err
+}
+
+func (s *CachedStorage) PurgeDeletedBefore(ctx context.Context, cutoff time.Time, batchSize int) ([]*model.Song, error) {
+	songs, err := s.SongStorage.PurgeDeletedBefore(ctx, cutoff, batchSize)
+	for _, song := range songs {
+		if song.ID != nil {
+			s.invalidateAfterCommit(ctx, *song.ID)
+		}
+	}
+
+	return songs, err
+}
+
+func (s *CachedStorage) listKey(filters model.SongFilter) (string, error) {
+	data, err := json.Marshal(filters)
+	if err != nil {
+		return "", fmt.Errorf("marshal song filter: %w", err)
+	}
+
+	return fmt.Sprintf("list:%d:%s", s.generation.Load(), data), nil
+}
+
+func songKey(id string, allowDeleted bool) string {
+	return fmt.Sprintf("song:%s:%t", id, allowDeleted)
+}
+
+// cloneSong copies the song so callers cannot change the cached one. Fields
+// are pointers that are replaced, never written through.
+func cloneSong(song *model.Song) *model.Song {
+	clone := *song
+	return &clone
+}
+
+func cloneSongs(songs []*model.Song) []*model.Song {
+	clones := make([]*model.Song, len(songs))
+	for i, song := range songs {
+		clones[i] = cloneSong(song)
+	}
+
+	return clones
+}
@@ -0,0 +1,115 @@
+package song
+
+import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"time"
+)
+
+const instrumentedStorageName = "song"
+
+type StorageObserver interface {
+	ObserveStorageOperation(storage, method string, err error, duration time.Duration)
+}
+
+// instrumentedStorage reports the latency of every storage call to the observer.
+type instrumentedStorage struct {
+	storage  service.SongStorage
+	observer StorageObserver
+}
+
+func NewInstrumentedStorage(storage service.SongStorage, observer StorageObserver) service.SongStorage {
+	return &instrumentedStorage{
+		storage:  storage,
+		observer: observer,
+	}
+}
+
+func (s *instrumentedStorage) observe(method string, start time.Time, err error) {
+	s.observer.ObserveStorageOperation(instrumentedStorageName, method, err, time.Since(start))
+}
+
+func (s *instrumentedStorage) GetByFilters(ctx context.Context, filters model.SongFilter) ([]*model.Song, error) {
+	start := time.Now()
+	result, err := s.storage.GetByFilters(ctx, filters)
+	s.observe("GetByFilters", start, err)
+	return result, err
+}
+
+func (s *instrumentedStorage) GetById(ctx context.Context, id string, allowDeleted bool) (*model.Song, error) {
+	start := time.Now()
+	result, err := s.storage.GetById(ctx, id, allowDeleted)
+	s.observe("GetById", start, err)
+	return result, err
+}
+
+func (s *instrumentedStorage) GetForUpdate(ctx context.Context, id string) (*model.Song, error) {
+	start := time.Now()
+	result, err := s.storage.GetForUpdate(ctx, id)
+	s.observe("GetForUpdate", start, err)
+	return result, err
+}
+
+func (s *instrumentedStorage) Search(ctx context.Context, query string, limit int) ([]*model.SongMatch, error) {
+	start := time.Now()
+	result, err := s.storage.Search(ctx, query, limit)
+	s.observe("Search", start, err)
+	return result, err
+}
+
+func (s *instrumentedStorage) Suggest(ctx context.Context, field model.SuggestField, prefix string, limit int) ([]*model.Suggestion, error) {
+	start := time.Now()
+	result, err := s.storage.Suggest(ctx, field, prefix, limit)
+	s.observe("Suggest", start, err)
+	return result, err
+}
+
+func (s *instrumentedStorage) Create(ctx context.Context, song model.Song) (*model.Song, error) {
+	start := time.Now()
+	result, err := s.storage.Create(ctx, song)
+	s.observe("Create", start, err)
+	return result, err
+}
+
+func (s *instrumentedStorage) Update(ctx context.Context, song model.Song) (*model.Song, error) {
+	start := time.Now()
+	result, err := s.storage.Update(ctx, song)
+	s.observe("Update", start, err)
+	return result, err
+}
+
+func (s *instrumentedStorage) Delete(ctx context.Context, id string) error {
+	start := time.Now()
+	err := s.storage.Delete(ctx, id)
+	s.observe("Delete", start, err)
+	return err
+}
+
+func (s *instrumentedStorage) Restore(ctx context.Context, id string) error {
+	start := time.Now()
+	err := s.storage.Restore(ctx, id)
+	s.observe("Restore", start, err)
+	return err
+}
+
+func (s *instrumentedStorage) DeletePermanent(ctx context.Context, id string) error {
+	start := time.Now()
+	err := s.storage.DeletePermanent(ctx, id)
+	s.observe("DeletePermanent", start, err)
+	return err
+}
+
+func (s *instrumentedStorage) PurgeDeletedBefore(ctx context.Context, cutoff time.Time, batchSize int) ([]*model.Song, error) {
+	start := time.Now()
+	result, err := s.storage.PurgeDeletedBefore(ctx, cutoff, batchSize)
+	s.observe("PurgeDeletedBefore", start, err)
+	return result, err
+}
+
+func (s *instrumentedStorage) CountDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	start := time.Now()
+	result, err := s.storage.CountDeletedBefore(ctx, cutoff)
+	s.observe("CountDeletedBefore", start, err)
+	return result, err
+}
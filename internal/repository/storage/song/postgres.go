@@ -4,107 +4,143 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/orungrau/em_song_library/internal/domain/model"
 	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/repository/postgres"
 	"github.com/rs/zerolog"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
-type PostgresStorageConfig interface {
-	GetConnection() string
-	GetDatabase() string
-	GetMigrationSource() string
-}
-
+// PostgresStorage also manages the unique index on group and title, whether
+// the index exists is the only record of uniqueness being enforced.
 type PostgresStorage interface {
 	service.SongStorage
-	MustAutoMigrate()
-	MustConnect()
-	Close()
+	EnforceUniqueness(ctx context.Context) error
+	RelaxUniqueness(ctx context.Context) error
+	UniquenessEnforced(ctx context.Context) (bool, error)
 }
 
+const (
+	uniqueViolationCode   = "23505"
+	undefinedFunctionCode = "42883"
+	uniqueSongsConstraint = "idx_songs_group_title_unique"
+	// maxReportedDuplicates bounds the duplicates listed when the index
+	// cannot be built.
+	maxReportedDuplicates = 10
+)
+
 type songPostgresStorage struct {
+	db   *postgres.Database
 	pool *pgxpool.Pool
 	log  zerolog.Logger
-	cfg  PostgresStorageConfig
 }
 
-func NewPostgresStorage(log zerolog.Logger, cfg PostgresStorageConfig) PostgresStorage {
+func NewPostgresStorage(log zerolog.Logger, db *postgres.Database) PostgresStorage {
 	return &songPostgresStorage{
-		cfg: cfg,
-		log: log.With().Str("module", "song-postgres-storage").Logger(),
+		db:   db,
+		pool: db.Pool(),
+		log:  log.With().Str("module", "song-postgres-storage").Logger(),
 	}
 }
 
-func (s *songPostgresStorage) MustConnect() {
-	ctx := context.Background()
-
-	pool, err := pgxpool.New(ctx, s.cfg.GetConnection())
+// EnforceUniqueness builds the unique index on normalized group and title of
+// songs not deleted. It is built without blocking writes, and refused with
+// the duplicates listed when existing songs would violate it.
+func (s *songPostgresStorage) EnforceUniqueness(ctx context.Context) error {
+	duplicates, err := s.duplicates(ctx)
 	if err != nil {
-		s.log.Fatal().Err(err).Msg("Failed to create PostgreSQL connection pool")
-		return
+		return err
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("resolve duplicated songs first: %s", strings.Join(duplicates, "; "))
 	}
 
-	if err = pool.Ping(ctx); err != nil {
-		s.log.Fatal().Err(err).Msg("Failed to connect to PostgreSQL database")
-		return
+	query := `
+		CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS ` + uniqueSongsConstraint + `
+		ON songs (lower(btrim("group")), lower(btrim(title)))
+		WHERE deleted_at IS NULL`
+
+	if _, err := s.pool.Exec(ctx, query); err != nil {
+		// A failed concurrent build leaves an invalid index behind
+		if _, dropErr := s.pool.Exec(context.Background(), `DROP INDEX CONCURRENTLY IF EXISTS `+uniqueSongsConstraint); dropErr != nil {
+			s.log.Error().Err(dropErr).Msg("Could not drop the invalid song uniqueness index")
+		}
+		return fmt.Errorf("create song uniqueness index: %w", err)
 	}
 
-	s.log.Info().Msg("Successfully connected to PostgreSQL database")
-	s.pool = pool
+	return nil
 }
 
-func (s *songPostgresStorage) Close() {
-	if s.pool != nil {
-		s.pool.Close()
-		s.log.Info().Msg("PostgreSQL connection pool closed")
+func (s *songPostgresStorage) RelaxUniqueness(ctx context.Context) error {
+	if _, err := s.pool.Exec(ctx, `DROP INDEX CONCURRENTLY IF EXISTS `+uniqueSongsConstraint); err != nil {
+		return fmt.Errorf("drop song uniqueness index: %w", err)
 	}
+
+	return nil
 }
 
-func (s *songPostgresStorage) MustAutoMigrate() {
-	db := stdlib.OpenDBFromPool(s.pool)
+func (s *songPostgresStorage) UniquenessEnforced(ctx context.Context) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM pg_index i
+			JOIN pg_class c ON c.oid = i.indexrelid
+			WHERE c.relname = $1 AND i.indisvalid
+		)`
 
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
-	if err != nil {
-		s.log.Fatal().Err(err).Msg("Could not create postgres driver")
+	var enforced bool
+	if err := s.pool.QueryRow(ctx, query, uniqueSongsConstraint).Scan(&enforced); err != nil {
+		return false, fmt.Errorf("check song uniqueness index: %w", err)
 	}
 
-	defer func() {
-		if err := driver.Close(); err != nil {
-			s.log.Fatal().Err(err).Msg("Error closing migrations connection")
-		}
-	}()
+	return enforced, nil
+}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		s.cfg.GetMigrationSource(),
-		s.cfg.GetDatabase(),
-		driver,
-	)
+// duplicates lists the groups and titles the unique index would reject.
+func (s *songPostgresStorage) duplicates(ctx context.Context) ([]string, error) {
+	query := `
+		SELECT min("group"), min(title), count(*)
+		FROM songs
+		WHERE deleted_at IS NULL
+		GROUP BY lower(btrim("group")), lower(btrim(title))
+		HAVING count(*) > 1
+		ORDER BY count(*) DESC
+		LIMIT $1`
+
+	rows, err := s.pool.Query(ctx, query, maxReportedDuplicates)
 	if err != nil {
-		s.log.Fatal().Err(err).Msg("Could not create migrate instance")
+		return nil, fmt.Errorf("find duplicated songs: %w", err)
 	}
+	defer rows.Close()
 
-	if err := m.Up(); err != nil {
-		if errors.Is(err, migrate.ErrNoChange) {
-			s.log.Info().Msg("No migrations to apply")
-		} else {
-			s.log.Fatal().Err(err).Msg("Could not apply migrations")
+	var duplicates []string
+	for rows.Next() {
+		var (
+			group, title string
+			count        int
+		)
+		if err := rows.Scan(&group, &title, &count); err != nil {
+			return nil, err
 		}
-		return
+		duplicates = append(duplicates, fmt.Sprintf("%s - %s (%d songs)", group, title, count))
 	}
 
-	s.log.Info().Msg("Migrations applied successfully")
+	return duplicates, rows.Err()
 }
 
 func (s *songPostgresStorage) GetByFilters(ctx context.Context, filters model.SongFilter) ([]*model.Song, error) {
+	fields := filters.Fields
+	if len(fields) == 0 {
+		fields = model.SongFields
+	}
+
 	query := `
-		SELECT id, title, text, link, "group", release_date, created_at, updated_at, deleted_at
+		SELECT ` + selectColumns(fields) + `
 		FROM songs
 		WHERE 1=1`
 	var args []interface{}
@@ -140,22 +176,40 @@ func (s *songPostgresStorage) GetByFilters(ctx context.Context, filters model.So
 		args = append(args, "%"+*filters.Group+"%")
 		argIndex++
 	}
+	if len(filters.IDs) > 0 {
+		query += fmt.Sprintf(" AND id = ANY($%d::uuid[])", argIndex)
+		args = append(args, filters.IDs)
+		argIndex++
+	}
+	if len(filters.Groups) > 0 {
+		query += fmt.Sprintf(" AND \"group\" = ANY($%d)", argIndex)
+		args = append(args, filters.Groups)
+		argIndex++
+	}
+
+	if filters.After != nil {
+		query += fmt.Sprintf(" AND (release_date, id) < ($%d, $%d::uuid)", argIndex, argIndex+1)
+		args = append(args, filters.After.ReleaseDate, filters.After.ID)
+		argIndex += 2
+	}
 
-	query += "  AND deleted_at IS NULL ORDER BY release_date DESC"
+	// The ID breaks ties between songs released at the same time, so pages
+	// neither repeat nor skip them
+	query += "  AND deleted_at IS NULL ORDER BY release_date DESC, id DESC"
 
 	if filters.PageSize >= 0 {
 		query += fmt.Sprintf(" LIMIT $%d", argIndex)
 		args = append(args, filters.PageSize)
 		argIndex++
 	}
-	if filters.Page >= 0 && filters.PageSize > 0 {
+	if filters.After == nil && filters.Page >= 0 && filters.PageSize > 0 {
 		offset := filters.Page * filters.PageSize
 		query += fmt.Sprintf(" OFFSET $%d", argIndex)
 		args = append(args, offset)
 		argIndex++
 	}
 
-	rows, err := s.pool.Query(ctx, query, args...)
+	rows, err := s.db.Conn(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -164,18 +218,7 @@ func (s *songPostgresStorage) GetByFilters(ctx context.Context, filters model.So
 	songs := make([]*model.Song, 0)
 	for rows.Next() {
 		var song model.Song
-		err := rows.Scan(
-			&song.ID,
-			&song.Title,
-			&song.Text,
-			&song.Link,
-			&song.Group,
-			&song.ReleaseDate,
-			&song.CreatedAt,
-			&song.UpdatedAt,
-			&song.DeletedAt,
-		)
-		if err != nil {
+		if err := rows.Scan(scanTargets(&song, fields)...); err != nil {
 			return nil, err
 		}
 		songs = append(songs, &song)
@@ -199,7 +242,38 @@ func (s *songPostgresStorage) GetById(ctx context.Context, id string, allowDelet
 	}
 
 	var song model.Song
-	err := s.pool.QueryRow(ctx, query, id).Scan(
+	err := s.db.Conn(ctx).QueryRow(ctx, query, id).Scan(
+		&song.ID,
+		&song.Title,
+		&song.Text,
+		&song.Link,
+		&song.Group,
+		&song.ReleaseDate,
+		&song.CreatedAt,
+		&song.UpdatedAt,
+		&song.DeletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &song, nil
+}
+
+// GetForUpdate reads a song, deleted or not, and locks its row until the
+// surrounding transaction ends.
+func (s *songPostgresStorage) GetForUpdate(ctx context.Context, id string) (*model.Song, error) {
+	query := `
+		SELECT id, title, text, link, "group", release_date, created_at, updated_at, deleted_at
+		FROM songs
+		WHERE id = $1
+		FOR UPDATE`
+
+	var song model.Song
+	err := s.db.Conn(ctx).QueryRow(ctx, query, id).Scan(
 		&song.ID,
 		&song.Title,
 		&song.Text,
@@ -220,15 +294,124 @@ func (s *songPostgresStorage) GetById(ctx context.Context, id string, allowDelet
 	return &song, nil
 }
 
+func (s *songPostgresStorage) Search(ctx context.Context, query string, limit int) ([]*model.SongMatch, error) {
+	sqlQuery := `
+		WITH normalized AS (
+			SELECT id, title, text, link, "group", release_date, created_at, updated_at, deleted_at,
+			       immutable_unaccent(lower(title)) AS search_title,
+			       immutable_unaccent(lower("group")) AS search_group,
+			       immutable_unaccent(lower($1)) AS search_query
+			FROM songs
+			WHERE deleted_at IS NULL
+		)
+		SELECT id, title, text, link, "group", release_date, created_at, updated_at, deleted_at,
+		       GREATEST(
+		           similarity(search_title, search_query),
+		           word_similarity(search_query, search_title),
+		           similarity(search_group, search_query),
+		           word_similarity(search_query, search_group)
+		       ) AS score
+		FROM normalized
+		WHERE search_title % search_query
+		   OR search_query <% search_title
+		   OR search_group % search_query
+		   OR search_query <% search_group
+		ORDER BY score DESC, title
+		LIMIT $2`
+
+	rows, err := s.db.Conn(ctx).Query(ctx, sqlQuery, query, limit)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == undefinedFunctionCode {
+			return nil, fmt.Errorf("%w: %v", service.ErrFuzzySearchUnavailable, err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := make([]*model.SongMatch, 0)
+	for rows.Next() {
+		var song model.Song
+		var score float64
+		err := rows.Scan(
+			&song.ID,
+			&song.Title,
+			&song.Text,
+			&song.Link,
+			&song.Group,
+			&song.ReleaseDate,
+			&song.CreatedAt,
+			&song.UpdatedAt,
+			&song.DeletedAt,
+			&score,
+		)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, &model.SongMatch{Song: &song, Score: score})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (s *songPostgresStorage) Suggest(ctx context.Context, field model.SuggestField, prefix string, limit int) ([]*model.Suggestion, error) {
+	var column string
+	switch field {
+	case model.SuggestFieldTitle:
+		column = "title"
+	case model.SuggestFieldGroup:
+		column = `"group"`
+	default:
+		return nil, fmt.Errorf("unknown suggest field: %s", field)
+	}
+
+	// The range is matched with the text_pattern_ops operators so that the
+	// prefix index is usable by generic plans of the cached statement.
+	query := `
+		SELECT min(` + column + `) AS value, count(*) AS frequency
+		FROM songs
+		WHERE deleted_at IS NULL
+		  AND lower(` + column + `) ~>=~ $1
+		  AND lower(` + column + `) ~<~ $2
+		GROUP BY lower(` + column + `)
+		ORDER BY frequency DESC, value
+		LIMIT $3`
+
+	rows, err := s.db.Conn(ctx).Query(ctx, query, prefix, prefix+string(utf8.MaxRune), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := make([]*model.Suggestion, 0)
+	for rows.Next() {
+		var suggestion model.Suggestion
+		if err := rows.Scan(&suggestion.Value, &suggestion.Frequency); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, &suggestion)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
 func (s *songPostgresStorage) Create(ctx context.Context, song model.Song) (*model.Song, error) {
 	query := `
 		INSERT INTO songs (title, text, link, "group", release_date, created_at, updated_at, deleted_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6::timestamp, CURRENT_TIMESTAMP::timestamp), COALESCE($7::timestamp, CURRENT_TIMESTAMP::timestamp), $8)
 		RETURNING id
 	`
 
 	var id string
-	err := s.pool.QueryRow(
+	err := s.db.Conn(ctx).QueryRow(
 		ctx,
 		query,
 		song.Title,
@@ -241,6 +424,9 @@ func (s *songPostgresStorage) Create(ctx context.Context, song model.Song) (*mod
 		song.DeletedAt,
 	).Scan(&id)
 	if err != nil {
+		if isUniqueSongsViolation(err) {
+			return nil, s.conflictError(ctx, song.Group, song.Title, nil)
+		}
 		return nil, err
 	}
 
@@ -283,14 +469,14 @@ func (s *songPostgresStorage) Update(ctx context.Context, song model.Song) (*mod
 		argIndex++
 	}
 
-	query = strings.TrimSuffix(query, ", ")
+	query += `updated_at = CURRENT_TIMESTAMP`
 
 	query += ` WHERE id = $` + fmt.Sprint(argIndex) + ` AND deleted_at IS NULL 
 		RETURNING id, title, text, link, "group", release_date, created_at, updated_at, deleted_at`
 	args = append(args, *song.ID)
 
 	var updatedSong model.Song
-	err := s.pool.QueryRow(ctx, query, args...).Scan(
+	err := s.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(
 		&updatedSong.ID,
 		&updatedSong.Title,
 		&updatedSong.Text,
@@ -302,9 +488,12 @@ func (s *songPostgresStorage) Update(ctx context.Context, song model.Song) (*mod
 		&updatedSong.DeletedAt,
 	)
 	if err != nil {
+		if isUniqueSongsViolation(err) {
+			return nil, s.conflictError(ctx, song.Group, song.Title, song.ID)
+		}
 		s.log.Err(err).Str("query", query).Msg("")
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, errors.New("song not found or already deleted")
+			return nil, fmt.Errorf("%w or already deleted", service.ErrSongNotFound)
 		}
 		return nil, err
 	}
@@ -319,13 +508,13 @@ func (s *songPostgresStorage) Delete(ctx context.Context, id string) error {
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	result, err := s.pool.Exec(ctx, query, id)
+	result, err := s.db.Conn(ctx).Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
 
 	if result.RowsAffected() == 0 {
-		return errors.New("song not found or already deleted")
+		return fmt.Errorf("%w or already deleted", service.ErrSongNotFound)
 	}
 
 	return nil
@@ -334,17 +523,21 @@ func (s *songPostgresStorage) Delete(ctx context.Context, id string) error {
 func (s *songPostgresStorage) Restore(ctx context.Context, id string) error {
 	query := `
 		UPDATE songs 
-		SET deleted_at = NULL 
+		SET deleted_at = NULL
 		WHERE id = $1 AND deleted_at IS NOT NULL
 	`
 
-	result, err := s.pool.Exec(ctx, query, id)
+	result, err := s.db.Conn(ctx).Exec(ctx, query, id)
 	if err != nil {
+		if isUniqueSongsViolation(err) {
+			// The restored song keeps its group and title, they come from its row
+			return s.conflictError(ctx, nil, nil, &id)
+		}
 		return err
 	}
 
 	if result.RowsAffected() == 0 {
-		return errors.New("song not found or not deleted")
+		return fmt.Errorf("%w or not deleted", service.ErrSongNotFound)
 	}
 
 	return nil
@@ -356,14 +549,148 @@ func (s *songPostgresStorage) DeletePermanent(ctx context.Context, id string) er
 		WHERE id = $1
 	`
 
-	result, err := s.pool.Exec(ctx, query, id)
+	result, err := s.db.Conn(ctx).Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
 
 	if result.RowsAffected() == 0 {
-		return errors.New("song not found")
+		return service.ErrSongNotFound
 	}
 
 	return nil
 }
+
+// selectColumns lists the columns of the requested fields, only songs that
+// are not deleted are listed so deleted_at is never needed.
+func selectColumns(fields []model.SongField) string {
+	columns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		switch field {
+		case model.SongFieldGroup:
+			columns = append(columns, `"group"`)
+		default:
+			columns = append(columns, string(field))
+		}
+	}
+
+	return strings.Join(columns, ", ")
+}
+
+func scanTargets(song *model.Song, fields []model.SongField) []any {
+	targets := make([]any, 0, len(fields))
+	for _, field := range fields {
+		switch field {
+		case model.SongFieldID:
+			targets = append(targets, &song.ID)
+		case model.SongFieldTitle:
+			targets = append(targets, &song.Title)
+		case model.SongFieldGroup:
+			targets = append(targets, &song.Group)
+		case model.SongFieldText:
+			targets = append(targets, &song.Text)
+		case model.SongFieldLink:
+			targets = append(targets, &song.Link)
+		case model.SongFieldReleaseDate:
+			targets = append(targets, &song.ReleaseDate)
+		case model.SongFieldCreatedAt:
+			targets = append(targets, &song.CreatedAt)
+		case model.SongFieldUpdatedAt:
+			targets = append(targets, &song.UpdatedAt)
+		}
+	}
+
+	return targets
+}
+
+func isUniqueSongsViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) &&
+		pgErr.Code == uniqueViolationCode &&
+		pgErr.ConstraintName == uniqueSongsConstraint
+}
+
+// conflictError looks up the song that blocked the write. Group and title
+// fall back to the values of the song being updated when they are not changed.
+// It runs on the pool because a failed statement aborts the caller's transaction.
+func (s *songPostgresStorage) conflictError(ctx context.Context, group, title, updatedID *string) error {
+	query := `
+		SELECT existing.id
+		FROM songs existing
+		LEFT JOIN songs target ON target.id = $3
+		WHERE lower(btrim(existing."group")) = lower(btrim(COALESCE(NULLIF($1, ''), target."group")))
+		  AND lower(btrim(existing.title)) = lower(btrim(COALESCE(NULLIF($2, ''), target.title)))
+		  AND existing.deleted_at IS NULL
+		  AND existing.id IS DISTINCT FROM target.id
+		LIMIT 1`
+
+	var existingID string
+	err := s.pool.QueryRow(ctx, query, group, title, updatedID).Scan(&existingID)
+	if err != nil {
+		return fmt.Errorf("song conflicts with an existing one: %w", err)
+	}
+
+	return &service.SongConflictError{ExistingID: existingID}
+}
+
+func (s *songPostgresStorage) PurgeDeletedBefore(ctx context.Context, cutoff time.Time, batchSize int) ([]*model.Song, error) {
+	query := `
+		DELETE FROM songs
+		WHERE id IN (
+			SELECT id
+			FROM songs
+			WHERE deleted_at IS NOT NULL AND deleted_at < $1
+			ORDER BY deleted_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, title, text, link, "group", release_date, created_at, updated_at, deleted_at
+	`
+
+	rows, err := s.db.Conn(ctx).Query(ctx, query, cutoff.UTC(), batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	purged := make([]*model.Song, 0)
+	for rows.Next() {
+		var song model.Song
+		err := rows.Scan(
+			&song.ID,
+			&song.Title,
+			&song.Text,
+			&song.Link,
+			&song.Group,
+			&song.ReleaseDate,
+			&song.CreatedAt,
+			&song.UpdatedAt,
+			&song.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		purged = append(purged, &song)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return purged, nil
+}
+
+func (s *songPostgresStorage) CountDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `
+		SELECT count(*)
+		FROM songs
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+	`
+
+	var count int64
+	if err := s.db.Conn(ctx).QueryRow(ctx, query, cutoff.UTC()).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,278 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/repository/postgres"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+const deliveryColumns = `
+	d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+	d.next_attempt_at, d.last_attempt_at, d.last_status_code, d.last_error, d.delivered_at, d.created_at`
+
+type webhookPostgresStorage struct {
+	db  *postgres.Database
+	log zerolog.Logger
+}
+
+func NewPostgresStorage(log zerolog.Logger, db *postgres.Database) service.WebhookStorage {
+	return &webhookPostgresStorage{
+		db:  db,
+		log: log.With().Str("module", "webhook-postgres-storage").Logger(),
+	}
+}
+
+func (s *webhookPostgresStorage) CreateSubscription(ctx context.Context, subscription model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	query := `
+		INSERT INTO webhook_subscriptions (url, events, secret)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+
+	err := s.db.Conn(ctx).QueryRow(ctx, query, subscription.URL, subscription.Events, subscription.Secret).
+		Scan(&subscription.ID, &subscription.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (s *webhookPostgresStorage) ListSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, events, secret, created_at, deleted_at
+		FROM webhook_subscriptions
+		ORDER BY created_at`
+
+	rows, err := s.db.Conn(ctx).Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*model.WebhookSubscription, 0)
+	for rows.Next() {
+		var subscription model.WebhookSubscription
+		err := rows.Scan(
+			&subscription.ID,
+			&subscription.URL,
+			&subscription.Events,
+			&subscription.Secret,
+			&subscription.CreatedAt,
+			&subscription.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, &subscription)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteSubscription keeps the subscription for the delivery log and moves
+// its pending deliveries to dead letters.
+func (s *webhookPostgresStorage) DeleteSubscription(ctx context.Context, id string) error {
+	query := `
+		WITH deleted AS (
+			UPDATE webhook_subscriptions
+			SET deleted_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND deleted_at IS NULL
+			RETURNING id
+		), abandoned AS (
+			UPDATE webhook_deliveries
+			SET status = 'dead', last_error = 'subscription deleted'
+			WHERE subscription_id IN (SELECT id FROM deleted) AND status = 'pending'
+		)
+		SELECT count(*) FROM deleted`
+
+	var deleted int
+	if err := s.db.Conn(ctx).QueryRow(ctx, query, id).Scan(&deleted); err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return service.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// Enqueue writes the deliveries with the transaction of the change when one
+// is open in ctx.
+func (s *webhookPostgresStorage) Enqueue(ctx context.Context, eventID, eventType string, payload []byte) error {
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+		SELECT id, $1, $2, $3
+		FROM webhook_subscriptions
+		WHERE deleted_at IS NULL AND $2 = ANY(events)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`
+
+	_, err := s.db.Conn(ctx).Exec(ctx, query, eventID, eventType, payload)
+
+	return err
+}
+
+// ClaimDue skips rows locked by other replicas, so each delivery is sent by
+// one of them, and pushes the next attempt past the lease.
+func (s *webhookPostgresStorage) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*model.WebhookAttempt, error) {
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY next_attempt_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1,
+		    last_attempt_at = CURRENT_TIMESTAMP,
+		    next_attempt_at = CURRENT_TIMESTAMP + $2::bigint * INTERVAL '1 millisecond'
+		FROM due, webhook_subscriptions s
+		WHERE d.id = due.id AND s.id = d.subscription_id
+		RETURNING ` + deliveryColumns + `, s.url, s.secret`
+
+	rows, err := s.db.Conn(ctx).Query(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := make([]*model.WebhookAttempt, 0)
+	for rows.Next() {
+		attempt := model.WebhookAttempt{Delivery: &model.WebhookDelivery{}}
+		if err := rows.Scan(append(deliveryTargets(attempt.Delivery), &attempt.URL, &attempt.Secret)...); err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, &attempt)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+func (s *webhookPostgresStorage) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'delivered', delivered_at = CURRENT_TIMESTAMP, last_status_code = $2, last_error = NULL
+		WHERE id = $1`
+
+	_, err := s.db.Conn(ctx).Exec(ctx, query, id, statusCode)
+
+	return err
+}
+
+func (s *webhookPostgresStorage) Reschedule(ctx context.Context, id int64, statusCode *int, reason string, delay time.Duration) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = CURRENT_TIMESTAMP + $4::bigint * INTERVAL '1 millisecond',
+		    last_status_code = $2,
+		    last_error = $3
+		WHERE id = $1`
+
+	_, err := s.db.Conn(ctx).Exec(ctx, query, id, statusCode, reason, delay.Milliseconds())
+
+	return err
+}
+
+func (s *webhookPostgresStorage) MarkDead(ctx context.Context, id int64, statusCode *int, reason string) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'dead', last_status_code = $2, last_error = $3
+		WHERE id = $1`
+
+	_, err := s.db.Conn(ctx).Exec(ctx, query, id, statusCode, reason)
+
+	return err
+}
+
+func (s *webhookPostgresStorage) ListDeliveries(ctx context.Context, filter model.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries d
+		WHERE d.subscription_id = $1 AND d.id > $2`
+	args := []interface{}{filter.SubscriptionID, filter.AfterID}
+	argIndex := 3
+
+	if filter.Status != nil {
+		query += fmt.Sprintf(" AND d.status = $%d", argIndex)
+		args = append(args, *filter.Status)
+		argIndex++
+	}
+
+	query += fmt.Sprintf(" ORDER BY d.id LIMIT $%d", argIndex)
+	args = append(args, filter.Limit)
+
+	rows, err := s.db.Conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]*model.WebhookDelivery, 0)
+	for rows.Next() {
+		var delivery model.WebhookDelivery
+		if err := rows.Scan(deliveryTargets(&delivery)...); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (s *webhookPostgresStorage) Redeliver(ctx context.Context, subscriptionID string, id int64) error {
+	query := `
+		UPDATE webhook_deliveries d
+		SET status = 'pending', attempts = 0, next_attempt_at = CURRENT_TIMESTAMP
+		FROM webhook_subscriptions s
+		WHERE d.id = $1
+		  AND d.subscription_id = $2
+		  AND d.status <> 'pending'
+		  AND s.id = d.subscription_id
+		  AND s.deleted_at IS NULL
+		RETURNING d.id`
+
+	var updated int64
+	err := s.db.Conn(ctx).QueryRow(ctx, query, id, subscriptionID).Scan(&updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return service.ErrWebhookDeliveryPending
+	}
+
+	return err
+}
+
+func deliveryTargets(delivery *model.WebhookDelivery) []any {
+	return []any{
+		&delivery.ID,
+		&delivery.SubscriptionID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastAttemptAt,
+		&delivery.LastStatusCode,
+		&delivery.LastError,
+		&delivery.DeliveredAt,
+		&delivery.CreatedAt,
+	}
+}
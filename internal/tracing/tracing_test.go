@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testConfig struct{}
+
+func (testConfig) GetEnabled() bool        { return false }
+func (testConfig) GetEndpoint() string     { return "" }
+func (testConfig) GetInsecure() bool       { return false }
+func (testConfig) GetSampleRatio() float64 { return 1 }
+
+// setupInMemory installs a tracer provider exporting to memory, the returned
+// function flushes it and returns the ended spans.
+func setupInMemory(t *testing.T) func() tracetest.SpanStubs {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	shutdown, err := Setup(context.Background(), testConfig{}, exporter)
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = shutdown(context.Background())
+	})
+
+	// Shutting the exporter down would clear it, flushing the batcher does not
+	provider := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	return func() tracetest.SpanStubs {
+		if err := provider.ForceFlush(context.Background()); err != nil {
+			t.Fatalf("ForceFlush() error = %v", err)
+		}
+		return exporter.GetSpans()
+	}
+}
+
+func TestTransportPropagatesTrace(t *testing.T) {
+	spans := setupInMemory(t)
+
+	var received trace.SpanContext
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		received = trace.SpanContextFromContext(ctx)
+	}))
+	defer server.Close()
+
+	ctx, parent := Tracer().Start(context.Background(), "parent")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	resp, err := (&http.Client{Transport: NewTransport(nil)}).Do(req)
+	if err != nil {
+		t.Fatalf("request error = %v", err)
+	}
+	resp.Body.Close()
+	parent.End()
+
+	ended := spans()
+	if len(ended) != 2 {
+		t.Fatalf("got %d spans, want the client and parent spans", len(ended))
+	}
+
+	client := ended[0]
+	if client.Name != "HTTP GET" || client.SpanKind != trace.SpanKindClient {
+		t.Fatalf("first span = %q of kind %s, want HTTP GET client span", client.Name, client.SpanKind)
+	}
+	if client.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Fatalf("client span parent = %s, want %s", client.Parent.SpanID(), parent.SpanContext().SpanID())
+	}
+	if received.TraceID() != parent.SpanContext().TraceID() || received.SpanID() != client.SpanContext.SpanID() {
+		t.Fatalf("server saw span %s/%s, want the client span of the same trace", received.TraceID(), received.SpanID())
+	}
+}
+
+func TestEndRecordsError(t *testing.T) {
+	spans := setupInMemory(t)
+
+	_, span := Tracer().Start(context.Background(), "failing")
+	End(span, errors.New("boom"))
+
+	ended := spans()
+	if len(ended) != 1 {
+		t.Fatalf("got %d spans, want 1", len(ended))
+	}
+	if ended[0].Status.Code != codes.Error || ended[0].Status.Description != "boom" {
+		t.Fatalf("span status = %v, want error boom", ended[0].Status)
+	}
+	if len(ended[0].Events) != 1 || ended[0].Events[0].Name != "exception" {
+		t.Fatalf("span events = %v, want the recorded exception", ended[0].Events)
+	}
+}
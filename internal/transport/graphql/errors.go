@@ -0,0 +1,57 @@
+package graphql
+
+import (
+	"errors"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+)
+
+const (
+	codeBadRequest = "BAD_REQUEST"
+	codeForbidden  = "FORBIDDEN"
+	codeConflict   = "CONFLICT"
+	codeNotFound   = "NOT_FOUND"
+	codeInternal   = "INTERNAL"
+)
+
+// resolverError is reported in the errors of a response with its code in the
+// extensions, so clients can tell failures apart without parsing messages.
+type resolverError struct {
+	message    string
+	extensions map[string]interface{}
+}
+
+func newResolverError(code, message string) *resolverError {
+	return &resolverError{message: message, extensions: map[string]interface{}{"code": code}}
+}
+
+func (e *resolverError) Error() string {
+	return e.message
+}
+
+func (e *resolverError) Extensions() map[string]interface{} {
+	return e.extensions
+}
+
+// serviceError turns song service errors into resolver errors with a code in
+// the extensions. A conflict also carries the ID of the existing song, and
+// unexpected errors are logged here since GraphQL reports them per field.
+func (r *Resolver) serviceError(err error) error {
+	var (
+		forbidden *service.ForbiddenError
+		conflict  *service.SongConflictError
+	)
+
+	switch {
+	case errors.As(err, &forbidden):
+		return newResolverError(codeForbidden, forbidden.Reason)
+	case errors.As(err, &conflict):
+		resolverErr := newResolverError(codeConflict, conflict.Error())
+		resolverErr.extensions["existingId"] = conflict.ExistingID
+		return resolverErr
+	case errors.Is(err, service.ErrSongNotFound):
+		return newResolverError(codeNotFound, service.ErrSongNotFound.Error())
+	default:
+		r.log.Error().Err(err).Msg("Song service call failed")
+		return newResolverError(codeInternal, "internal error")
+	}
+}
@@ -0,0 +1,69 @@
+package graphql
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/trace/otel"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/transport/http/utils"
+	"github.com/rs/zerolog"
+	"net/http"
+)
+
+const (
+	// maxDepth bounds nesting such as song.related.related, each level of
+	// which costs a batch of queries.
+	maxDepth = 5
+	// maxQueryLength bounds the length of the query text in characters, the
+	// songs it resolves are counted by the loaders.
+	maxQueryLength = 8 << 10
+	// maxBodyBytes leaves room for variables next to the longest query.
+	maxBodyBytes = 64 << 10
+)
+
+//go:embed schema.graphql
+var schema string
+
+type Handler struct {
+	schema      *graphql.Schema
+	songService service.SongService
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func NewHandler(log zerolog.Logger, songService service.SongService) *Handler {
+	return &Handler{
+		schema: graphql.MustParseSchema(
+			schema,
+			NewResolver(log, songService),
+			graphql.MaxDepth(maxDepth),
+			graphql.MaxQueryLength(maxQueryLength),
+			graphql.Tracer(otel.DefaultTracer()),
+		),
+		songService: songService,
+	}
+}
+
+// ServeHTTP executes a query sent as JSON in the request body. Each request
+// gets its own loaders so batched results are never shared between callers.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.WriteError(w, r, http.StatusRequestEntityTooLarge, "Request body is too large")
+			return
+		}
+		utils.WriteError(w, r, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+
+	ctx := withLoaders(r.Context(), newLoaders(h.songService))
+	utils.WriteJson(w, h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables), http.StatusOK)
+}
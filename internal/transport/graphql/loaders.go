@@ -0,0 +1,120 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/pkg/dataloader"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	loaderWait     = 2 * time.Millisecond
+	loaderMaxBatch = 100
+	// maxGroupSongs is all related songs ever need, the limit plus the song
+	// itself.
+	maxGroupSongs = maxRelatedSongs + 1
+	// maxResolvedSongs bounds the songs a single request can resolve, which
+	// caps queries made wide with aliases or nested lists.
+	maxResolvedSongs = 1000
+)
+
+// loaders batch the lookups of nested fields, so lyrics or related songs of a
+// page of songs cost one query each instead of one per song. They also count
+// the songs resolved by the request.
+type loaders struct {
+	text     *dataloader.Loader[string, *string]
+	group    *dataloader.Loader[string, []*model.Song]
+	resolved atomic.Int64
+}
+
+type loadersKey struct{}
+
+func newLoaders(songService service.SongService) *loaders {
+	return &loaders{
+		text: dataloader.New(func(ctx context.Context, ids []string) (map[string]*string, error) {
+			songs, err := songService.GetByFilters(ctx, model.SongFilter{
+				IDs:      ids,
+				PageSize: len(ids),
+				Fields:   []model.SongField{model.SongFieldID, model.SongFieldText},
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			texts := make(map[string]*string, len(songs))
+			for _, song := range songs {
+				texts[stringValue(song.ID)] = song.Text
+			}
+
+			return texts, nil
+		}, loaderWait, loaderMaxBatch),
+		group: dataloader.New(func(ctx context.Context, groups []string) (map[string][]*model.Song, error) {
+			return loadGroups(ctx, songService, groups)
+		}, loaderWait, loaderMaxBatch),
+	}
+}
+
+// loadGroups reads up to maxGroupSongs newest songs of each group with one
+// query. When the shared page fills up a large group may have crowded out the
+// others, which are then read one by one.
+func loadGroups(ctx context.Context, songService service.SongService, groups []string) (map[string][]*model.Song, error) {
+	pageSize := len(groups) * maxGroupSongs
+	songs, err := songService.GetByFilters(ctx, model.SongFilter{
+		Groups:   groups,
+		PageSize: pageSize,
+		Fields:   listSongFields,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byGroup := make(map[string][]*model.Song, len(groups))
+	for _, song := range songs {
+		group := stringValue(song.Group)
+		if len(byGroup[group]) < maxGroupSongs {
+			byGroup[group] = append(byGroup[group], song)
+		}
+	}
+
+	if len(songs) < pageSize {
+		return byGroup, nil
+	}
+
+	for _, group := range groups {
+		if len(byGroup[group]) == maxGroupSongs {
+			continue
+		}
+
+		songs, err := songService.GetByFilters(ctx, model.SongFilter{
+			Groups:   []string{group},
+			PageSize: maxGroupSongs,
+			Fields:   listSongFields,
+		})
+		if err != nil {
+			return nil, err
+		}
+		byGroup[group] = songs
+	}
+
+	return byGroup, nil
+}
+
+// reserve counts n more resolved songs against the request budget.
+func (l *loaders) reserve(n int) error {
+	if l.resolved.Add(int64(n)) > maxResolvedSongs {
+		return newResolverError(codeBadRequest, fmt.Sprintf("query resolves more than %d songs", maxResolvedSongs))
+	}
+
+	return nil
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFromContext(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,248 @@
+package graphql
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+const maxPageSize = 100
+
+// listSongFields are read for song lists, lyrics are loaded in batches only
+// when a query selects them.
+var listSongFields = []model.SongField{
+	model.SongFieldID,
+	model.SongFieldTitle,
+	model.SongFieldGroup,
+	model.SongFieldLink,
+	model.SongFieldReleaseDate,
+	model.SongFieldCreatedAt,
+	model.SongFieldUpdatedAt,
+}
+
+// Resolver is the root resolver of queries and mutations, every call goes
+// through the song service so the policy applies like on the other transports.
+type Resolver struct {
+	log         zerolog.Logger
+	songService service.SongService
+}
+
+func NewResolver(log zerolog.Logger, songService service.SongService) *Resolver {
+	return &Resolver{
+		log:         log.With().Str("module", "graphql-transport").Logger(),
+		songService: songService,
+	}
+}
+
+type songFilterInput struct {
+	Title           *string
+	Group           *string
+	Text            *string
+	Link            *string
+	ReleaseDateFrom *graphql.Time
+	ReleaseDateTo   *graphql.Time
+}
+
+type createSongInput struct {
+	Title       string
+	Group       string
+	Text        *string
+	Link        *string
+	ReleaseDate graphql.Time
+}
+
+type updateSongInput struct {
+	Title       *string
+	Group       *string
+	Text        *string
+	Link        *string
+	ReleaseDate *graphql.Time
+}
+
+func (r *Resolver) Songs(ctx context.Context, args struct {
+	Filter   *songFilterInput
+	Page     int32
+	PageSize int32
+}) ([]*songResolver, error) {
+	if err := validatePage(args.Page, args.PageSize); err != nil {
+		return nil, err
+	}
+
+	filter := model.SongFilter{
+		Page:     int(args.Page),
+		PageSize: int(args.PageSize),
+		Fields:   listSongFields,
+	}
+	if args.Filter != nil {
+		filter.Title = args.Filter.Title
+		filter.Group = args.Filter.Group
+		filter.Text = args.Filter.Text
+		filter.Link = args.Filter.Link
+		filter.ReleaseDateFrom = timeValue(args.Filter.ReleaseDateFrom)
+		filter.ReleaseDateTo = timeValue(args.Filter.ReleaseDateTo)
+	}
+
+	return r.listSongs(ctx, filter)
+}
+
+func (r *Resolver) Song(ctx context.Context, args struct{ ID graphql.ID }) (*songResolver, error) {
+	id, err := songID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	song, err := r.songService.Get(ctx, id)
+	if err != nil {
+		return nil, r.serviceError(err)
+	}
+	if song == nil {
+		return nil, nil
+	}
+	if err := loadersFromContext(ctx).reserve(1); err != nil {
+		return nil, err
+	}
+
+	return r.newSongResolver(song, true), nil
+}
+
+func (r *Resolver) Group(ctx context.Context, args struct{ Name string }) (*groupResolver, error) {
+	songs, err := r.songService.GetByFilters(ctx, model.SongFilter{
+		Groups:   []string{args.Name},
+		PageSize: 1,
+		Fields:   []model.SongField{model.SongFieldID},
+	})
+	if err != nil {
+		return nil, r.serviceError(err)
+	}
+	if len(songs) == 0 {
+		return nil, nil
+	}
+
+	return &groupResolver{root: r, name: args.Name}, nil
+}
+
+func (r *Resolver) CreateSong(ctx context.Context, args struct{ Input createSongInput }) (*songResolver, error) {
+	if args.Input.Title == "" || args.Input.Group == "" {
+		return nil, newResolverError(codeBadRequest, "title and group are required")
+	}
+
+	input := args.Input
+	song, err := r.songService.Create(ctx, model.NewSong(input.Title, input.Group, input.Text, input.Link, &input.ReleaseDate.Time))
+	if err != nil {
+		return nil, r.serviceError(err)
+	}
+
+	return r.newSongResolver(song, true), nil
+}
+
+func (r *Resolver) UpdateSong(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input updateSongInput
+}) (*songResolver, error) {
+	id, err := songID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	song, err := r.songService.Update(ctx, model.Song{
+		ID:          &id,
+		Title:       args.Input.Title,
+		Group:       args.Input.Group,
+		Text:        args.Input.Text,
+		Link:        args.Input.Link,
+		ReleaseDate: timeValue(args.Input.ReleaseDate),
+	})
+	if err != nil {
+		return nil, r.serviceError(err)
+	}
+
+	return r.newSongResolver(song, true), nil
+}
+
+func (r *Resolver) DeleteSong(ctx context.Context, args struct {
+	ID        graphql.ID
+	Permanent bool
+}) (bool, error) {
+	id, err := songID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if args.Permanent {
+		err = r.songService.DeletePermanent(ctx, id)
+	} else {
+		err = r.songService.Delete(ctx, id)
+	}
+	if err != nil {
+		return false, r.serviceError(err)
+	}
+
+	return true, nil
+}
+
+func (r *Resolver) RestoreSong(ctx context.Context, args struct{ ID graphql.ID }) (*songResolver, error) {
+	id, err := songID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.songService.Restore(ctx, id); err != nil {
+		return nil, r.serviceError(err)
+	}
+
+	song, err := r.songService.Get(ctx, id)
+	if err != nil {
+		return nil, r.serviceError(err)
+	}
+	if song == nil {
+		return nil, r.serviceError(service.ErrSongNotFound)
+	}
+
+	return r.newSongResolver(song, true), nil
+}
+
+func (r *Resolver) listSongs(ctx context.Context, filter model.SongFilter) ([]*songResolver, error) {
+	songs, err := r.songService.GetByFilters(ctx, filter)
+	if err != nil {
+		return nil, r.serviceError(err)
+	}
+	if err := loadersFromContext(ctx).reserve(len(songs)); err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*songResolver, 0, len(songs))
+	for _, song := range songs {
+		resolvers = append(resolvers, r.newSongResolver(song, false))
+	}
+
+	return resolvers, nil
+}
+
+func validatePage(page, pageSize int32) error {
+	if page < 0 || pageSize < 1 || pageSize > maxPageSize {
+		return newResolverError(codeBadRequest, "page must be non-negative and pageSize between 1 and 100")
+	}
+
+	return nil
+}
+
+// songID rejects song IDs that are not UUIDs before they reach the database.
+func songID(id graphql.ID) (string, error) {
+	if _, err := uuid.Parse(string(id)); err != nil {
+		return "", newResolverError(codeBadRequest, "invalid song ID")
+	}
+
+	return string(id), nil
+}
+
+func timeValue(t *graphql.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	return &t.Time
+}
@@ -0,0 +1,176 @@
+package graphql
+
+import (
+	"context"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const maxRelatedSongs = 20
+
+var verseSeparator = regexp.MustCompile(`\n\s*\n`)
+
+type songResolver struct {
+	root *Resolver
+	song *model.Song
+	// textLoaded is false for songs read without lyrics.
+	textLoaded bool
+}
+
+func (r *Resolver) newSongResolver(song *model.Song, textLoaded bool) *songResolver {
+	return &songResolver{root: r, song: song, textLoaded: textLoaded}
+}
+
+func (s *songResolver) ID() graphql.ID {
+	return graphql.ID(stringValue(s.song.ID))
+}
+
+func (s *songResolver) Title() string {
+	return stringValue(s.song.Title)
+}
+
+func (s *songResolver) Group() *groupResolver {
+	return &groupResolver{root: s.root, name: stringValue(s.song.Group)}
+}
+
+func (s *songResolver) Text(ctx context.Context) (*string, error) {
+	if s.textLoaded {
+		return s.song.Text, nil
+	}
+
+	text, err := loadersFromContext(ctx).text.Load(ctx, stringValue(s.song.ID))
+	if err != nil {
+		return nil, s.root.serviceError(err)
+	}
+
+	return text, nil
+}
+
+func (s *songResolver) Verses(ctx context.Context) ([]*verseResolver, error) {
+	text, err := s.Text(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return splitVerses(stringValue(text)), nil
+}
+
+func (s *songResolver) Link() *string {
+	return s.song.Link
+}
+
+func (s *songResolver) ReleaseDate() *graphql.Time {
+	return timeResult(s.song.ReleaseDate)
+}
+
+func (s *songResolver) CreatedAt() *graphql.Time {
+	return timeResult(s.song.CreatedAt)
+}
+
+func (s *songResolver) UpdatedAt() *graphql.Time {
+	return timeResult(s.song.UpdatedAt)
+}
+
+func (s *songResolver) Related(ctx context.Context, args struct{ Limit int32 }) ([]*songResolver, error) {
+	if args.Limit < 0 || args.Limit > maxRelatedSongs {
+		return nil, newResolverError(codeBadRequest, "limit must be between 0 and 20")
+	}
+
+	songs, err := loadersFromContext(ctx).group.Load(ctx, stringValue(s.song.Group))
+	if err != nil {
+		return nil, s.root.serviceError(err)
+	}
+
+	related := make([]*songResolver, 0, args.Limit)
+	for _, song := range songs {
+		if len(related) == int(args.Limit) {
+			break
+		}
+		if stringValue(song.ID) != stringValue(s.song.ID) {
+			related = append(related, s.root.newSongResolver(song, false))
+		}
+	}
+	if err := loadersFromContext(ctx).reserve(len(related)); err != nil {
+		return nil, err
+	}
+
+	return related, nil
+}
+
+type groupResolver struct {
+	root *Resolver
+	name string
+}
+
+func (g *groupResolver) Name() string {
+	return g.name
+}
+
+func (g *groupResolver) Songs(ctx context.Context, args struct {
+	Page     int32
+	PageSize int32
+}) ([]*songResolver, error) {
+	if err := validatePage(args.Page, args.PageSize); err != nil {
+		return nil, err
+	}
+
+	return g.root.listSongs(ctx, model.SongFilter{
+		Groups:   []string{g.name},
+		Page:     int(args.Page),
+		PageSize: int(args.PageSize),
+		Fields:   listSongFields,
+	})
+}
+
+type verseResolver struct {
+	number int32
+	text   string
+}
+
+func (v *verseResolver) Number() int32 {
+	return v.number
+}
+
+func (v *verseResolver) Text() string {
+	return v.text
+}
+
+func (v *verseResolver) Lines() []string {
+	return strings.Split(v.text, "\n")
+}
+
+// splitVerses splits lyrics on blank lines, the convention used by the
+// lyrics stored in the library.
+func splitVerses(text string) []*verseResolver {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if text == "" {
+		return []*verseResolver{}
+	}
+
+	parts := verseSeparator.Split(text, -1)
+	verses := make([]*verseResolver, 0, len(parts))
+	for _, part := range parts {
+		verses = append(verses, &verseResolver{number: int32(len(verses) + 1), text: strings.TrimSpace(part)})
+	}
+
+	return verses
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+func timeResult(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+
+	return &graphql.Time{Time: *t}
+}
@@ -0,0 +1,65 @@
+package grpc
+
+import (
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	songv1 "github.com/orungrau/em_song_library/pkg/api/song/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+func songFromModel(song *model.Song) *songv1.Song {
+	return &songv1.Song{
+		Id:          stringValue(song.ID),
+		Title:       stringValue(song.Title),
+		Group:       stringValue(song.Group),
+		Text:        song.Text,
+		Link:        song.Link,
+		ReleaseDate: timestampFromTime(song.ReleaseDate),
+		CreatedAt:   timestampFromTime(song.CreatedAt),
+		UpdatedAt:   timestampFromTime(song.UpdatedAt),
+	}
+}
+
+func filterToModel(filter *songv1.SongFilter) model.SongFilter {
+	if filter == nil {
+		return model.SongFilter{}
+	}
+
+	return model.SongFilter{
+		ReleaseDateFrom: timeFromTimestamp(filter.GetReleaseDateFrom()),
+		ReleaseDateTo:   timeFromTimestamp(filter.GetReleaseDateTo()),
+		Title:           filter.Title,
+		Text:            filter.Text,
+		Link:            filter.Link,
+		Group:           filter.Group,
+	}
+}
+
+func createToModel(req *songv1.CreateSongRequest) model.Song {
+	return model.NewSong(req.GetTitle(), req.GetGroup(), req.Text, req.Link, timeFromTimestamp(req.GetReleaseDate()))
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+func timestampFromTime(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
+
+func timeFromTimestamp(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+
+	t := ts.AsTime()
+	return &t
+}
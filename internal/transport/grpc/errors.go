@@ -0,0 +1,28 @@
+package grpc
+
+import (
+	"errors"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// serviceError maps song service errors to gRPC status codes. Unexpected
+// errors become a bare Internal status, the cause is logged by the interceptor.
+func serviceError(err error) error {
+	var (
+		forbidden *service.ForbiddenError
+		conflict  *service.SongConflictError
+	)
+
+	switch {
+	case errors.As(err, &forbidden):
+		return status.Error(codes.PermissionDenied, forbidden.Reason)
+	case errors.As(err, &conflict):
+		return status.Error(codes.AlreadyExists, conflict.Error())
+	case errors.Is(err, service.ErrSongNotFound):
+		return status.Error(codes.NotFound, service.ErrSongNotFound.Error())
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
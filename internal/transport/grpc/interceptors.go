@@ -0,0 +1,179 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/orungrau/em_song_library/internal/auth"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/pkg/logger"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	apiKeyMetadata        = "x-api-key"
+	authorizationMetadata = "authorization"
+	requestIDMetadata     = "x-request-id"
+	bearerPrefix          = "Bearer "
+	maxRequestIDLength    = 128
+)
+
+// publicServices are reachable without credentials so probes and tooling
+// work with authentication enabled.
+var publicServices = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
+type CallObserver interface {
+	ObserveGRPCCall(method, code string, duration time.Duration)
+}
+
+// Interceptors resolve the request, log and measure the call, recover from
+// panics, throttle by IP address and authenticate the caller, mirroring the
+// HTTP middleware chain.
+type Interceptors struct {
+	log           zerolog.Logger
+	authenticator *auth.Authenticator
+	limiter       *RateLimiter
+	observer      CallObserver
+}
+
+func NewInterceptors(log zerolog.Logger, authenticator *auth.Authenticator, limiter *RateLimiter, observer CallObserver) *Interceptors {
+	return &Interceptors{
+		log:           log.With().Str("module", "grpc-transport").Logger(),
+		authenticator: authenticator,
+		limiter:       limiter,
+		observer:      observer,
+	}
+}
+
+func (i *Interceptors) Unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	start := time.Now()
+	ctx, err = i.begin(ctx, info.FullMethod, start)
+	defer i.finish(ctx, info.FullMethod, start, &err)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (i *Interceptors) Stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	start := time.Now()
+	ctx, err := i.begin(ss.Context(), info.FullMethod, start)
+	defer i.finish(ctx, info.FullMethod, start, &err)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// begin records the request and resolves the principal of the call.
+func (i *Interceptors) begin(ctx context.Context, method string, start time.Time) (context.Context, error) {
+	ctx = logger.WithStartTime(ctx, start)
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	id := firstValue(md, requestIDMetadata)
+	if id == "" || len(id) > maxRequestIDLength {
+		id = uuid.NewString()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, id))
+
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+	ctx = actor.WithRequest(ctx, &actor.Request{ID: id, IP: ip})
+
+	if isPublic(method) {
+		return ctx, nil
+	}
+
+	if err := i.limiter.allowIP(ctx); err != nil {
+		return ctx, err
+	}
+
+	creds := auth.Credentials{APIKey: firstValue(md, apiKeyMetadata)}
+	if authorization := firstValue(md, authorizationMetadata); strings.HasPrefix(authorization, bearerPrefix) {
+		creds.BearerToken = strings.TrimPrefix(authorization, bearerPrefix)
+	}
+
+	principal, err := i.authenticator.Authenticate(ctx, creds)
+	switch {
+	case err == nil:
+		return actor.WithPrincipal(ctx, principal), nil
+	case errors.Is(err, auth.ErrInvalidToken):
+		return ctx, status.Error(codes.Unauthenticated, "invalid bearer token")
+	case errors.Is(err, service.ErrInvalidAPIKey), errors.Is(err, auth.ErrMissingCredentials):
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
+	default:
+		i.log.Error().Ctx(ctx).Err(err).Msg("Could not authenticate call")
+		return ctx, status.Error(codes.Internal, "internal error")
+	}
+}
+
+// finish turns a panic into an internal error, logs the outcome of the call
+// and records it in the metrics.
+func (i *Interceptors) finish(ctx context.Context, method string, start time.Time, err *error) {
+	logContext := i.log.With().Str("method", method)
+	if request, ok := actor.RequestFromContext(ctx); ok {
+		logContext = logContext.Str("request_id", request.ID)
+	}
+	log := logContext.Logger()
+
+	if rec := recover(); rec != nil {
+		log.Error().Interface("panic", rec).Msg("Recovered from panic")
+		*err = status.Error(codes.Internal, "internal error")
+	}
+
+	code := status.Code(*err)
+	i.observer.ObserveGRPCCall(method, code.String(), time.Since(start))
+
+	if code != codes.OK {
+		log.Error().Ctx(ctx).Str("code", code.String()).Msg("gRPC call completed with error")
+	} else {
+		log.Info().Ctx(ctx).Str("code", code.String()).Msg("gRPC call completed successfully")
+	}
+}
+
+func isPublic(method string) bool {
+	for _, prefix := range publicServices {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// serverStream carries the context built by the interceptor to stream handlers.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
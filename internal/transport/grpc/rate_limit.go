@@ -0,0 +1,125 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/pkg/ratelimit"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"math"
+	"strconv"
+)
+
+// Rule names match the HTTP middleware so both transports draw from the same
+// buckets when they share a store.
+const (
+	ipRateLimitRule      = "ip"
+	defaultRateLimitRule = "default"
+	retryAfterMetadata   = "retry-after"
+)
+
+type RateLimitConfig interface {
+	GetEnabled() bool
+	GetIPLimit() string
+	GetDefaultLimit() string
+}
+
+// RateLimiter throttles calls with the token buckets of the HTTP API, per IP
+// address before authentication and per client after it.
+type RateLimiter struct {
+	log          zerolog.Logger
+	store        ratelimit.Store
+	enabled      bool
+	ipLimit      ratelimit.Limit
+	defaultLimit ratelimit.Limit
+}
+
+func NewRateLimiter(log zerolog.Logger, store ratelimit.Store, cfg RateLimitConfig) (*RateLimiter, error) {
+	ipLimit, err := ratelimit.ParseLimit(cfg.GetIPLimit())
+	if err != nil {
+		return nil, fmt.Errorf("ip: %w", err)
+	}
+
+	defaultLimit, err := ratelimit.ParseLimit(cfg.GetDefaultLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	return &RateLimiter{
+		log:          log.With().Str("module", "grpc-rate-limit").Logger(),
+		store:        store,
+		enabled:      cfg.GetEnabled(),
+		ipLimit:      ipLimit,
+		defaultLimit: defaultLimit,
+	}, nil
+}
+
+// Unary and Stream run after the request interceptors and limit the
+// authenticated client, health checks and reflection are not limited.
+func (l *RateLimiter) Unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := l.allowClient(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (l *RateLimiter) Stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := l.allowClient(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+
+	return handler(srv, ss)
+}
+
+// allowIP is called by the request interceptors before the credentials are
+// checked, so failed authentication attempts count against the limit too.
+func (l *RateLimiter) allowIP(ctx context.Context) error {
+	return l.allow(ctx, ipRateLimitRule, ipKey(ctx), l.ipLimit)
+}
+
+func (l *RateLimiter) allowClient(ctx context.Context, method string) error {
+	if isPublic(method) {
+		return nil
+	}
+
+	key := ipKey(ctx)
+	if principal, ok := actor.PrincipalFromContext(ctx); ok && principal.Method != actor.MethodAnonymous {
+		key = principal.Method + ":" + principal.ID
+	}
+
+	return l.allow(ctx, defaultRateLimitRule, key, l.defaultLimit)
+}
+
+func (l *RateLimiter) allow(ctx context.Context, rule, client string, limit ratelimit.Limit) error {
+	if l == nil || !l.enabled {
+		return nil
+	}
+
+	result, err := l.store.Allow(ctx, rule+"|"+client, limit)
+	if err != nil {
+		// An unavailable store must not take the API down with it
+		l.log.Error().Err(err).Ctx(ctx).Msg("Rate limit store failed, allowing call")
+		return nil
+	}
+
+	if !result.Allowed {
+		retryAfter := max(1, int(math.Ceil(result.RetryAfter.Seconds())))
+		_ = grpc.SetHeader(ctx, metadata.Pairs(retryAfterMetadata, strconv.Itoa(retryAfter)))
+		return status.Errorf(codes.ResourceExhausted, "rate limit of %d requests per %s exceeded", limit.Requests, limit.Period)
+	}
+
+	return nil
+}
+
+func ipKey(ctx context.Context) string {
+	if request, ok := actor.RequestFromContext(ctx); ok {
+		return "ip:" + request.IP
+	}
+
+	return "ip:"
+}
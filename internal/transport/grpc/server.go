@@ -0,0 +1,28 @@
+package grpc
+
+import (
+	"github.com/orungrau/em_song_library/internal/auth"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	songv1 "github.com/orungrau/em_song_library/pkg/api/song/v1"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewServer registers the song service and reflection on a gRPC server that
+// traces, logs, measures, rate limits and authenticates every call. Options
+// such as transport credentials are passed on to the server.
+func NewServer(log zerolog.Logger, songService service.SongService, authenticator *auth.Authenticator, limiter *RateLimiter, observer CallObserver, opts ...grpc.ServerOption) *grpc.Server {
+	interceptors := NewInterceptors(log, authenticator, limiter, observer)
+
+	server := grpc.NewServer(append([]grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(interceptors.Unary, limiter.Unary),
+		grpc.ChainStreamInterceptor(interceptors.Stream, limiter.Stream),
+	}, opts...)...)
+	songv1.RegisterSongServiceServer(server, NewSongServer(songService))
+	reflection.Register(server)
+
+	return server
+}
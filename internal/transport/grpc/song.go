@@ -0,0 +1,177 @@
+package grpc
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	songv1 "github.com/orungrau/em_song_library/pkg/api/song/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	defaultPageSize = 10
+	maxPageSize     = 1000
+	exportPageSize  = 500
+)
+
+// SongServer serves the song library over gRPC with the same service the
+// HTTP handlers use.
+type SongServer struct {
+	songv1.UnimplementedSongServiceServer
+	songService service.SongService
+}
+
+func NewSongServer(songService service.SongService) *SongServer {
+	return &SongServer{songService: songService}
+}
+
+func (s *SongServer) ListSongs(ctx context.Context, req *songv1.ListSongsRequest) (*songv1.ListSongsResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize < 0 || pageSize > maxPageSize || req.GetPage() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "page must be non-negative and page_size between 1 and %d", maxPageSize)
+	}
+
+	filter := filterToModel(req.GetFilter())
+	filter.Page = int(req.GetPage())
+	filter.PageSize = pageSize
+
+	songs, err := s.songService.GetByFilters(ctx, filter)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	resp := &songv1.ListSongsResponse{
+		Songs:    make([]*songv1.Song, 0, len(songs)),
+		Page:     req.GetPage(),
+		PageSize: int32(pageSize),
+	}
+	for _, song := range songs {
+		resp.Songs = append(resp.Songs, songFromModel(song))
+	}
+
+	return resp, nil
+}
+
+func (s *SongServer) GetSong(ctx context.Context, req *songv1.GetSongRequest) (*songv1.Song, error) {
+	if err := validateID(req.GetId()); err != nil {
+		return nil, err
+	}
+
+	song, err := s.songService.Get(ctx, req.GetId())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	if song == nil {
+		return nil, status.Error(codes.NotFound, service.ErrSongNotFound.Error())
+	}
+
+	return songFromModel(song), nil
+}
+
+func (s *SongServer) CreateSong(ctx context.Context, req *songv1.CreateSongRequest) (*songv1.Song, error) {
+	if req.GetTitle() == "" || req.GetGroup() == "" {
+		return nil, status.Error(codes.InvalidArgument, "title and group are required")
+	}
+	if req.GetReleaseDate() == nil {
+		return nil, status.Error(codes.InvalidArgument, "release_date is required")
+	}
+
+	song, err := s.songService.Create(ctx, createToModel(req))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return songFromModel(song), nil
+}
+
+func (s *SongServer) UpdateSong(ctx context.Context, req *songv1.UpdateSongRequest) (*songv1.Song, error) {
+	if err := validateID(req.GetId()); err != nil {
+		return nil, err
+	}
+
+	id := req.GetId()
+	song, err := s.songService.Update(ctx, model.Song{
+		ID:          &id,
+		Title:       req.Title,
+		Group:       req.Group,
+		Text:        req.Text,
+		Link:        req.Link,
+		ReleaseDate: timeFromTimestamp(req.GetReleaseDate()),
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return songFromModel(song), nil
+}
+
+func (s *SongServer) DeleteSong(ctx context.Context, req *songv1.DeleteSongRequest) (*emptypb.Empty, error) {
+	if err := validateID(req.GetId()); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if req.GetPermanent() {
+		err = s.songService.DeletePermanent(ctx, req.GetId())
+	} else {
+		err = s.songService.Delete(ctx, req.GetId())
+	}
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *SongServer) RestoreSong(ctx context.Context, req *songv1.RestoreSongRequest) (*emptypb.Empty, error) {
+	if err := validateID(req.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.songService.Restore(ctx, req.GetId()); err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ExportSongs pages through the library so a large export never holds more
+// than one page in memory.
+func (s *SongServer) ExportSongs(req *songv1.ExportSongsRequest, stream grpc.ServerStreamingServer[songv1.Song]) error {
+	filter := filterToModel(req.GetFilter())
+	filter.PageSize = exportPageSize
+
+	for {
+		songs, err := s.songService.GetByFilters(stream.Context(), filter)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		for _, song := range songs {
+			if err := stream.Send(songFromModel(song)); err != nil {
+				return err
+			}
+		}
+
+		if len(songs) < exportPageSize {
+			return nil
+		}
+		filter.After = model.CursorAfter(songs)
+	}
+}
+
+// validateID rejects song IDs that are not UUIDs before they reach the database.
+func validateID(id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return status.Error(codes.InvalidArgument, "invalid song ID")
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"time"
+)
+
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+} // @name APIKey
+
+func APIKeyFromModel(key *model.APIKey) APIKey {
+	return APIKey{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		CreatedAt:  key.CreatedAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
+
+type CreateAPIKey struct {
+	Name   string   `json:"name" validate:"required,max=255"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,oneof=songs:read songs:write songs:admin"`
+} // @name CreateAPIKey
+
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+} // @name CreatedAPIKey
+
+type APIKeyList struct {
+	Data []APIKey `json:"data"`
+} // @name APIKeyList
@@ -0,0 +1,88 @@
+package dto
+
+import (
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"time"
+)
+
+type AuditQuery struct {
+	Entity  *string `schema:"entity" validate:"omitempty,oneof=song"`
+	ID      *string `schema:"id" validate:"omitempty,max=255"`
+	Actor   *string `schema:"actor" validate:"omitempty,max=255"`
+	From    *string `schema:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To      *string `schema:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	AfterID int64   `schema:"after_id" validate:"min=0"`
+	Limit   int     `schema:"limit,default:100" validate:"min=1,max=1000"`
+}
+
+// ToModel converts the query, From and To must have passed validation.
+func (q *AuditQuery) ToModel() model.AuditFilter {
+	filter := model.AuditFilter{
+		Entity:   q.Entity,
+		EntityID: q.ID,
+		ActorID:  q.Actor,
+		AfterID:  q.AfterID,
+		Limit:    q.Limit,
+	}
+
+	if q.From != nil {
+		from, _ := time.Parse(time.RFC3339, *q.From)
+		filter.From = &from
+	}
+	if q.To != nil {
+		to, _ := time.Parse(time.RFC3339, *q.To)
+		filter.To = &to
+	}
+
+	return filter
+}
+
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+} // @name AuditChange
+
+type AuditEntry struct {
+	ID          int64                  `json:"id"`
+	OccurredAt  time.Time              `json:"occurred_at"`
+	Entity      string                 `json:"entity"`
+	EntityID    string                 `json:"entity_id"`
+	Action      string                 `json:"action"`
+	ActorID     string                 `json:"actor_id"`
+	ActorName   string                 `json:"actor_name"`
+	ActorMethod string                 `json:"actor_method"`
+	RequestID   *string                `json:"request_id"`
+	IP          *string                `json:"ip"`
+	Before      map[string]any         `json:"before"`
+	After       map[string]any         `json:"after"`
+	Changes     map[string]AuditChange `json:"changes"`
+} // @name AuditEntry
+
+func AuditEntryFromModel(entry *model.AuditEntry) AuditEntry {
+	changes := make(map[string]AuditChange, len(entry.Changes))
+	for field, change := range entry.Changes {
+		changes[field] = AuditChange{Before: change.Before, After: change.After}
+	}
+
+	return AuditEntry{
+		ID:          entry.ID,
+		OccurredAt:  entry.OccurredAt,
+		Entity:      entry.Entity,
+		EntityID:    entry.EntityID,
+		Action:      entry.Action,
+		ActorID:     entry.ActorID,
+		ActorName:   entry.ActorName,
+		ActorMethod: entry.ActorMethod,
+		RequestID:   entry.RequestID,
+		IP:          entry.IP,
+		Before:      entry.Before,
+		After:       entry.After,
+		Changes:     changes,
+	}
+}
+
+type AuditList struct {
+	Data []AuditEntry `json:"data"`
+	// NextAfterID is passed as after_id to fetch the next page, it is null on the last one.
+	NextAfterID *int64 `json:"next_after_id"`
+} // @name AuditList
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"time"
+)
+
+// SongChangeEvent is the data of a change feed event, Song is null once the
+// song is deleted permanently.
+type SongChangeEvent struct {
+	ID         int64          `json:"id"`
+	Change     string         `json:"change" enums:"created,updated,deleted,restored,deleted_permanently"`
+	SongID     string         `json:"song_id"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	Song       map[string]any `json:"song"`
+} // @name SongChangeEvent
+
+func SongChangeEventFromModel(event *model.SongChangeEvent) SongChangeEvent {
+	return SongChangeEvent{
+		ID:         event.ID,
+		Change:     string(event.Change),
+		SongID:     event.SongID,
+		OccurredAt: event.OccurredAt.UTC(),
+		Song:       event.Song,
+	}
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "github.com/orungrau/em_song_library/internal/health"
+
+type HealthCheck struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	Optional   bool    `json:"optional"`
+	DurationMs float64 `json:"duration_ms"`
+} // @name HealthCheck
+
+type HealthReport struct {
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks"`
+} // @name HealthReport
+
+func HealthReportFromModel(report health.Report) HealthReport {
+	checks := make([]HealthCheck, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		checks = append(checks, HealthCheck{
+			Name:       check.Name,
+			Status:     check.Status,
+			Error:      check.Error,
+			Optional:   check.Optional,
+			DurationMs: float64(check.Duration.Microseconds()) / 1000,
+		})
+	}
+
+	return HealthReport{
+		Status: report.Status,
+		Checks: checks,
+	}
+}
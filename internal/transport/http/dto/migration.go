@@ -0,0 +1,21 @@
+package dto
+
+import "github.com/orungrau/em_song_library/internal/domain/model"
+
+type MigrationStatus struct {
+	Mode    string `json:"mode"`
+	Version uint   `json:"version"`
+	Dirty   bool   `json:"dirty"`
+	Latest  uint   `json:"latest"`
+	Pending []uint `json:"pending"`
+} // @name MigrationStatus
+
+func MigrationStatusFromModel(status *model.MigrationStatus) MigrationStatus {
+	return MigrationStatus{
+		Mode:    status.Mode,
+		Version: status.Version,
+		Dirty:   status.Dirty,
+		Latest:  status.Latest,
+		Pending: status.Pending,
+	}
+}
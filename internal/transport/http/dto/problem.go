@@ -0,0 +1,27 @@
+package dto
+
+const (
+	ProblemTypeDefault    = "about:blank"
+	ProblemTypeValidation = "/problems/validation-error"
+	ProblemTypeConflict   = "/problems/conflict"
+	ProblemTypeForbidden  = "/problems/forbidden"
+	ProblemTypeRateLimit  = "/problems/rate-limited"
+)
+
+// Problem is an RFC 7807 problem details object.
+type Problem struct {
+	Type     string           `json:"type"`
+	Title    string           `json:"title"`
+	Status   int              `json:"status"`
+	Detail   string           `json:"detail,omitempty"`
+	Instance string           `json:"instance,omitempty"`
+	Errors   []FieldViolation `json:"errors,omitempty"`
+	// ExistingID is set on conflicts and points to the song blocking the write.
+	ExistingID string `json:"existing_id,omitempty"`
+} // @name Problem
+
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+} // @name FieldViolation
@@ -1,37 +1,179 @@
 package dto
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"github.com/orungrau/em_song_library/internal/domain/model"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type Song struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Text        *string   `json:"text"`
-	Link        *string   `json:"link"`
-	Group       string    `json:"group"`
-	ReleaseDate time.Time `json:"release_date"`
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Group       string     `json:"group"`
+	Text        *string    `json:"text"`
+	Link        *string    `json:"link"`
+	ReleaseDate time.Time  `json:"release_date"`
+	CreatedAt   *time.Time `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+
+	// fields limits the marshalled fields, all of them when nil.
+	fields []model.SongField
 } // @name Song
 
-func SongFromModel(song *model.Song) Song {
-	return Song{
-		ID:          *song.ID,
-		Title:       *song.Title,
-		Group:       *song.Group,
-		ReleaseDate: *song.ReleaseDate,
+// ListSongFields are returned by list endpoints unless requested otherwise,
+// lyrics are left out to keep pages small.
+var ListSongFields = []model.SongField{
+	model.SongFieldID,
+	model.SongFieldTitle,
+	model.SongFieldGroup,
+	model.SongFieldLink,
+	model.SongFieldReleaseDate,
+	model.SongFieldCreatedAt,
+	model.SongFieldUpdatedAt,
+}
+
+// SongFromModel maps a song with the given fields, a nil fields maps all of
+// them. This is the only mapping from model.Song used by the handlers.
+func SongFromModel(song *model.Song, fields []model.SongField) Song {
+	result := Song{
+		Text:      song.Text,
+		Link:      song.Link,
+		CreatedAt: song.CreatedAt,
+		UpdatedAt: song.UpdatedAt,
+		fields:    fields,
+	}
+
+	if song.ID != nil {
+		result.ID = *song.ID
+	}
+	if song.Title != nil {
+		result.Title = *song.Title
+	}
+	if song.Group != nil {
+		result.Group = *song.Group
+	}
+	if song.ReleaseDate != nil {
+		result.ReleaseDate = *song.ReleaseDate
+	}
+
+	return result
+}
+
+func (m Song) MarshalJSON() ([]byte, error) {
+	type song Song
+	if m.fields == nil {
+		return json.Marshal(song(m))
+	}
+
+	values := map[model.SongField]any{
+		model.SongFieldID:          m.ID,
+		model.SongFieldTitle:       m.Title,
+		model.SongFieldGroup:       m.Group,
+		model.SongFieldText:        m.Text,
+		model.SongFieldLink:        m.Link,
+		model.SongFieldReleaseDate: m.ReleaseDate,
+		model.SongFieldCreatedAt:   m.CreatedAt,
+		model.SongFieldUpdatedAt:   m.UpdatedAt,
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	// Walk the canonical order so sparse objects keep the field order of full ones
+	for _, field := range model.SongFields {
+		if !slices.Contains(m.fields, field) {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		value, err := json.Marshal(values[field])
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(strconv.Quote(string(field)))
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// SongFields is the sparse fieldset of a request: fields replaces the
+// default fields and include adds to them, both are comma separated.
+type SongFields struct {
+	Fields  *string `json:"fields" schema:"fields"`
+	Include *string `json:"include" schema:"include"`
+}
+
+// Resolve returns the requested fields, or defaults when none are requested.
+func (f SongFields) Resolve(defaults []model.SongField) ([]model.SongField, error) {
+	result := defaults
+	if f.Fields != nil {
+		parsed, err := parseSongFields(*f.Fields)
+		if err != nil {
+			return nil, err
+		}
+		result = parsed
 	}
+
+	if f.Include != nil {
+		included, err := parseSongFields(*f.Include)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			// Nothing is left out by default, include has nothing to add
+			return nil, nil
+		}
+		for _, field := range included {
+			if !slices.Contains(result, field) {
+				result = append(slices.Clone(result), field)
+			}
+		}
+	}
+
+	return result, nil
 }
 
-func (m *Song) ToModel() model.Song {
-	return model.Song{
-		ID:          &m.ID,
-		Title:       &m.Title,
-		Group:       &m.Group,
-		ReleaseDate: &m.ReleaseDate,
+func parseSongFields(value string) ([]model.SongField, error) {
+	fields := make([]model.SongField, 0)
+	for _, name := range strings.Split(value, ",") {
+		field := model.SongField(strings.TrimSpace(name))
+		if field == "" {
+			continue
+		}
+		if !model.IsValidSongField(field) {
+			return nil, fmt.Errorf("unknown song field: %s", field)
+		}
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
 	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("at least one song field is required")
+	}
+
+	return fields, nil
 }
 
+// UpdateSong changes only the fields that are set, so empty ones are not
+// validated.
+type UpdateSong struct {
+	Title       string    `json:"title" validate:"omitempty,max=255"`
+	Text        *string   `json:"text"`
+	Link        *string   `json:"link" validate:"omitempty,max=255"`
+	Group       string    `json:"group" validate:"omitempty,max=255"`
+	ReleaseDate time.Time `json:"release_date"`
+} // @name UpdateSong
+
 type SongFilter struct {
 	ReleaseDateFrom *time.Time `json:"release_date_from" schema:"release_date_from"`
 	ReleaseDateTo   *time.Time `json:"release_date_to" schema:"release_date_from"`
@@ -41,18 +183,56 @@ type SongFilter struct {
 	Group           *string    `json:"group"`
 	Page            int        `json:"page" schema:"page,default:0"`
 	PageSize        int        `json:"page_size" schema:"page_size,default:10"`
+	SongFields
 }
 
 type CreateSong struct {
-	Title       string        `json:"title"`
+	Title       string        `json:"title" validate:"required,max=255"`
 	Text        *string       `json:"text,omitempty"`
-	Link        *string       `json:"link,omitempty" `
-	Group       string        `json:"group"`
-	ReleaseDate TimestampTime `json:"release_date" swaggertype:"primitive,integer"`
+	Link        *string       `json:"link,omitempty" validate:"omitempty,max=255"`
+	Group       string        `json:"group" validate:"required,max=255"`
+	ReleaseDate TimestampTime `json:"release_date" swaggertype:"primitive,integer" validate:"required"`
 } // @name CreateSong
 
+func (c *CreateSong) ToModel() model.Song {
+	return model.NewSong(c.Title, c.Group, c.Text, c.Link, &c.ReleaseDate.Time)
+}
+
 type SongList struct {
 	Data     []Song `json:"data"`
 	Page     int    `json:"page"`
 	PageSize int    `json:"page_size"`
 } // @name SongList
+
+type SongSearch struct {
+	Query string `json:"q" schema:"q" validate:"required"`
+	Limit int    `json:"limit" schema:"limit,default:10" validate:"min=1,max=100"`
+	SongFields
+}
+
+type SongMatch struct {
+	Song  Song    `json:"song"`
+	Score float64 `json:"score"`
+} // @name SongMatch
+
+type SongSearchResult struct {
+	Data  []SongMatch `json:"data"`
+	Query string      `json:"query"`
+} // @name SongSearchResult
+
+type SuggestQuery struct {
+	Prefix string `json:"prefix" schema:"prefix" validate:"required"`
+	Field  string `json:"field" schema:"field,default:title" validate:"oneof=title group"`
+	Limit  int    `json:"limit" schema:"limit,default:10" validate:"min=1,max=50"`
+}
+
+type Suggestion struct {
+	Value     string `json:"value"`
+	Frequency int    `json:"frequency"`
+} // @name Suggestion
+
+type SuggestionList struct {
+	Data   []Suggestion `json:"data"`
+	Field  string       `json:"field"`
+	Prefix string       `json:"prefix"`
+} // @name SuggestionList
@@ -0,0 +1,132 @@
+package dto
+
+import (
+	"encoding/json"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSongFieldsResolve(t *testing.T) {
+	text := func(s string) *string { return &s }
+	defaults := []model.SongField{model.SongFieldID, model.SongFieldTitle}
+
+	tests := []struct {
+		name     string
+		fields   SongFields
+		defaults []model.SongField
+		want     []model.SongField
+		wantErr  bool
+	}{
+		{name: "defaults", defaults: defaults, want: defaults},
+		{name: "all by default", defaults: nil, want: nil},
+		{
+			name:     "fields replace defaults",
+			fields:   SongFields{Fields: text("group, id,group")},
+			defaults: defaults,
+			want:     []model.SongField{model.SongFieldGroup, model.SongFieldID},
+		},
+		{
+			name:     "include adds to defaults",
+			fields:   SongFields{Include: text("text,title")},
+			defaults: defaults,
+			want:     []model.SongField{model.SongFieldID, model.SongFieldTitle, model.SongFieldText},
+		},
+		{
+			name:     "include adds to fields",
+			fields:   SongFields{Fields: text("id"), Include: text("link")},
+			defaults: defaults,
+			want:     []model.SongField{model.SongFieldID, model.SongFieldLink},
+		},
+		{
+			name:     "include with all fields",
+			fields:   SongFields{Include: text("text")},
+			defaults: nil,
+			want:     nil,
+		},
+		{name: "unknown field", fields: SongFields{Fields: text("id,lyrics")}, defaults: defaults, wantErr: true},
+		{name: "empty fields", fields: SongFields{Fields: text(" , ")}, defaults: defaults, wantErr: true},
+		{name: "unknown include", fields: SongFields{Include: text("lyrics")}, defaults: defaults, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fields.Resolve(tt.defaults)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("Resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("defaults are not modified", func(t *testing.T) {
+		before := slices.Clone(defaults)
+		if _, err := (SongFields{Include: text("text")}).Resolve(defaults); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(defaults, before) {
+			t.Errorf("defaults = %v, want %v", defaults, before)
+		}
+	})
+}
+
+func TestSongMarshalJSON(t *testing.T) {
+	id, title, group, link := "1", "Hysteria", "Muse", "https://example.com"
+	releaseDate := time.Date(2003, time.December, 1, 0, 0, 0, 0, time.UTC)
+	song := &model.Song{ID: &id, Title: &title, Group: &group, Link: &link, ReleaseDate: &releaseDate}
+
+	tests := []struct {
+		name   string
+		fields []model.SongField
+		want   string
+	}{
+		{
+			name: "all fields",
+			want: `{"id":"1","title":"Hysteria","group":"Muse","text":null,"link":"https://example.com","release_date":"2003-12-01T00:00:00Z","created_at":null,"updated_at":null}`,
+		},
+		{
+			name:   "list fields leave out text",
+			fields: ListSongFields,
+			want:   `{"id":"1","title":"Hysteria","group":"Muse","link":"https://example.com","release_date":"2003-12-01T00:00:00Z","created_at":null,"updated_at":null}`,
+		},
+		{
+			name:   "canonical order",
+			fields: []model.SongField{model.SongFieldText, model.SongFieldGroup, model.SongFieldID},
+			want:   `{"id":"1","group":"Muse","text":null}`,
+		},
+		{name: "no fields", fields: []model.SongField{}, want: `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(SongFromModel(song, tt.fields))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("json = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateSongToModelKeepsEveryField(t *testing.T) {
+	text, link := "lyrics", "https://example.com"
+	releaseDate := time.Date(2003, time.December, 1, 0, 0, 0, 0, time.UTC)
+	create := CreateSong{Title: "Hysteria", Group: "Muse", Text: &text, Link: &link, ReleaseDate: TimestampTime{releaseDate}}
+
+	song := create.ToModel()
+
+	if *song.Title != "Hysteria" || *song.Group != "Muse" {
+		t.Errorf("title and group = %q, %q, want Hysteria, Muse", *song.Title, *song.Group)
+	}
+	if song.Text == nil || *song.Text != text || song.Link == nil || *song.Link != link {
+		t.Errorf("text and link = %v, %v, want %q, %q", song.Text, song.Link, text, link)
+	}
+	if song.ReleaseDate == nil || !song.ReleaseDate.Equal(releaseDate) {
+		t.Errorf("release date = %v, want %v", song.ReleaseDate, releaseDate)
+	}
+}
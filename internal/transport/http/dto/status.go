@@ -4,3 +4,9 @@ type Status struct {
 	Error   bool   `json:"error"`
 	Message string `json:"message"`
 } // @name Status
+
+type ConflictStatus struct {
+	Error      bool   `json:"error"`
+	Message    string `json:"message"`
+	ExistingID string `json:"existing_id"`
+} // @name ConflictStatus
@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"encoding/json"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"time"
+)
+
+type Webhook struct {
+	ID        string     `json:"id"`
+	URL       string     `json:"url"`
+	Events    []string   `json:"events"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+} // @name Webhook
+
+func WebhookFromModel(subscription *model.WebhookSubscription) Webhook {
+	return Webhook{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Events:    subscription.Events,
+		CreatedAt: subscription.CreatedAt,
+		DeletedAt: subscription.DeletedAt,
+	}
+}
+
+type CreateWebhook struct {
+	URL    string   `json:"url" validate:"required,max=2048,http_url"`
+	Events []string `json:"events" validate:"required,min=1,unique,dive,oneof=song.created song.updated song.deleted song.restored song.deleted_permanently"`
+	// Secret signs the deliveries, one is generated when it is empty
+	Secret string `json:"secret" validate:"omitempty,min=16,max=255"`
+} // @name CreateWebhook
+
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+} // @name CreatedWebhook
+
+type WebhookList struct {
+	Data []Webhook `json:"data"`
+} // @name WebhookList
+
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status" enums:"pending,delivered,dead"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at"`
+	LastStatusCode *int            `json:"last_status_code"`
+	LastError      *string         `json:"last_error"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+} // @name WebhookDelivery
+
+func WebhookDeliveryFromModel(delivery *model.WebhookDelivery) WebhookDelivery {
+	return WebhookDelivery{
+		ID:             delivery.ID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
+
+type WebhookDeliveryList struct {
+	Data []WebhookDelivery `json:"data"`
+	// NextAfterID continues the listing, it is absent on the last page
+	NextAfterID *int64 `json:"next_after_id,omitempty"`
+} // @name WebhookDeliveryList
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/transport/http/dto"
+	"github.com/orungrau/em_song_library/internal/transport/http/utils"
+	"net/http"
+)
+
+type MigrationStatusProvider interface {
+	MigrationStatus(ctx context.Context) (*model.MigrationStatus, error)
+}
+
+type AdminHandler struct {
+	migrations MigrationStatusProvider
+}
+
+func NewAdminHandler(migrations MigrationStatusProvider) *AdminHandler {
+	return &AdminHandler{
+		migrations: migrations,
+	}
+}
+
+// MigrationStatus godoc
+// @Summary Database migration status
+// @Description Report the migration mode, applied schema version, dirty flag and migrations not applied yet.
+// @Tags admin
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {object} dto.MigrationStatus "Current migration status"
+// @Failure 500 {object} dto.Problem "Status could not be read"
+// @Router /admin/migrations [get]
+func (h *AdminHandler) MigrationStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.migrations.MigrationStatus(r.Context())
+	if err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	utils.WriteJson(w, dto.MigrationStatusFromModel(status), http.StatusOK)
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/transport/http/dto"
+	"github.com/orungrau/em_song_library/internal/transport/http/utils"
+	"net/http"
+)
+
+type APIKeyHandler struct {
+	validate      *validator.Validate
+	apiKeyService service.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		validate:      utils.NewValidator(),
+		apiKeyService: apiKeyService,
+	}
+}
+
+// List godoc
+// @Summary List API keys
+// @Description List all API keys including revoked ones. Key values are never returned.
+// @Tags api-keys
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {object} dto.APIKeyList "API keys"
+// @Failure 401 {object} dto.Problem "Authentication required"
+// @Failure 403 {object} dto.Problem "Missing scope"
+// @Router /api-keys [get]
+func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.apiKeyService.List(r.Context())
+	if err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	keysDto := make([]dto.APIKey, 0)
+
+	for _, i := range keys {
+		keysDto = append(keysDto, dto.APIKeyFromModel(i))
+	}
+
+	utils.WriteJson(w, dto.APIKeyList{Data: keysDto}, http.StatusOK)
+}
+
+// Create godoc
+// @Summary Create an API key
+// @Description Mint a new API key with the given scopes. The key value is returned only once.
+// @Tags api-keys
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param key body dto.CreateAPIKey true "Name and scopes of the key"
+// @Success 201 {object} dto.CreatedAPIKey "The created key with its value"
+// @Failure 400 {object} dto.Problem "Bad request error with a detailed message"
+// @Failure 401 {object} dto.Problem "Authentication required"
+// @Failure 403 {object} dto.Problem "Missing scope"
+// @Router /api-keys [post]
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var createDTO dto.CreateAPIKey
+
+	if err := json.NewDecoder(r.Body).Decode(&createDTO); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+
+	if err := h.validate.Struct(createDTO); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	key, plaintext, err := h.apiKeyService.Create(r.Context(), createDTO.Name, createDTO.Scopes)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	utils.WriteJson(w, dto.CreatedAPIKey{
+		APIKey: dto.APIKeyFromModel(key),
+		Key:    plaintext,
+	}, http.StatusCreated)
+}
+
+// Revoke godoc
+// @Summary Revoke an API key
+// @Description Revoke an API key so it can no longer authenticate.
+// @Tags api-keys
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param keyId path string true "ID of the key to revoke"
+// @Success 200 {object} dto.Status "Confirmation of revocation"
+// @Failure 400 {object} dto.Problem "Bad request error with a detailed message"
+// @Failure 404 {object} dto.Problem "Key not found or already revoked"
+// @Router /api-keys/{keyId} [delete]
+func (h *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	keyId := chi.URLParam(r, "keyId")
+
+	if _, err := uuid.Parse(keyId); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, "Invalid key ID")
+		return
+	}
+
+	err := h.apiKeyService.Revoke(r.Context(), keyId)
+	if err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			utils.WriteError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.WriteError(w, r, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	utils.WriteJson(w, dto.Status{
+		Error:   false,
+		Message: fmt.Sprintf("API key revoked with id: %s", keyId),
+	}, http.StatusOK)
+}
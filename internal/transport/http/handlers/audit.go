@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/schema"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/transport/http/dto"
+	"github.com/orungrau/em_song_library/internal/transport/http/utils"
+	"net/http"
+	"time"
+)
+
+const auditExportPageSize = 500
+
+type AuditHandler struct {
+	validate     *validator.Validate
+	decoder      *schema.Decoder
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	decoder := schema.NewDecoder()
+
+	decoder.IgnoreUnknownKeys(true)
+	decoder.ZeroEmpty(true)
+
+	return &AuditHandler{
+		validate:     utils.NewValidator(),
+		decoder:      decoder,
+		auditService: auditService,
+	}
+}
+
+// List godoc
+// @Summary Query the audit log
+// @Description List recorded mutations ordered by ID. Use next_after_id as after_id to fetch the following page.
+// @Tags audit
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param entity query string false "Audited entity" Enums(song)
+// @Param id query string false "ID of the audited entity"
+// @Param actor query string false "ID of the principal that made the change"
+// @Param from query string false "Include changes made at or after this RFC 3339 time"
+// @Param to query string false "Include changes made before this RFC 3339 time"
+// @Param after_id query int false "Return entries with a greater ID"
+// @Param limit query int false "Page size (default: 100, max: 1000)"
+// @Success 200 {object} dto.AuditList "Audit entries"
+// @Failure 400 {object} dto.Problem "Bad request error with a detailed message"
+// @Failure 401 {object} dto.Problem "Authentication required"
+// @Failure 403 {object} dto.Problem "Missing role"
+// @Router /audit [get]
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	query, ok := h.decodeQuery(w, r)
+	if !ok {
+		return
+	}
+
+	entries, err := h.auditService.List(r.Context(), query.ToModel())
+	if err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	entriesDto := make([]dto.AuditEntry, 0)
+
+	for _, i := range entries {
+		entriesDto = append(entriesDto, dto.AuditEntryFromModel(i))
+	}
+
+	response := dto.AuditList{Data: entriesDto}
+	if len(entries) == query.Limit {
+		response.NextAfterID = &entries[len(entries)-1].ID
+	}
+
+	utils.WriteJson(w, response, http.StatusOK)
+}
+
+// Export godoc
+// @Summary Export the audit log
+// @Description Stream every audit entry matching the filters as NDJSON, one entry per line.
+// @Tags audit
+// @Produce  application/x-ndjson
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param entity query string false "Audited entity" Enums(song)
+// @Param id query string false "ID of the audited entity"
+// @Param actor query string false "ID of the principal that made the change"
+// @Param from query string false "Include changes made at or after this RFC 3339 time"
+// @Param to query string false "Include changes made before this RFC 3339 time"
+// @Success 200 {object} dto.AuditEntry "One audit entry per line"
+// @Failure 400 {object} dto.Problem "Bad request error with a detailed message"
+// @Failure 401 {object} dto.Problem "Authentication required"
+// @Failure 403 {object} dto.Problem "Missing role"
+// @Router /audit/export [get]
+func (h *AuditHandler) Export(w http.ResponseWriter, r *http.Request) {
+	query, ok := h.decodeQuery(w, r)
+	if !ok {
+		return
+	}
+
+	filter := query.ToModel()
+	filter.Limit = auditExportPageSize
+
+	// The first page is read before writing so errors can still get a status code
+	entries, err := h.auditService.List(r.Context(), filter)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	// Exports of a long audit log outlive the server write timeout
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit.ndjson"`)
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	for {
+		for _, entry := range entries {
+			if err := encoder.Encode(dto.AuditEntryFromModel(entry)); err != nil {
+				return
+			}
+		}
+
+		if len(entries) < filter.Limit {
+			return
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+
+		filter.AfterID = entries[len(entries)-1].ID
+		if entries, err = h.auditService.List(r.Context(), filter); err != nil {
+			// Headers are gone, cutting the stream short is the only signal left
+			return
+		}
+	}
+}
+
+func (h *AuditHandler) decodeQuery(w http.ResponseWriter, r *http.Request) (*dto.AuditQuery, bool) {
+	if err := r.ParseForm(); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, "Failed to parse form")
+		return nil, false
+	}
+
+	var query dto.AuditQuery
+	if err := h.decoder.Decode(&query, r.Form); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, "Failed to decode audit query")
+		return nil, false
+	}
+
+	if err := h.validate.Struct(query); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return nil, false
+	}
+
+	return &query, true
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/transport/http/dto"
+	"github.com/orungrau/em_song_library/internal/transport/http/utils"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	lastEventIDHeader      = "Last-Event-ID"
+	changeFeedBatchSize    = 500
+	changeFeedRetryMillis  = 3000
+	changeFeedAfterParam   = "after"
+	changeFeedLatestCursor = -1
+)
+
+type ChangeFeedConfig interface {
+	GetPollInterval() time.Duration
+	GetHeartbeatInterval() time.Duration
+}
+
+type ChangeFeedHandler struct {
+	changeFeed service.ChangeFeedService
+	cfg        ChangeFeedConfig
+}
+
+func NewChangeFeedHandler(changeFeed service.ChangeFeedService, cfg ChangeFeedConfig) *ChangeFeedHandler {
+	return &ChangeFeedHandler{
+		changeFeed: changeFeed,
+		cfg:        cfg,
+	}
+}
+
+// Stream godoc
+// @Summary Follow song changes
+// @Description Stream song changes as Server-Sent Events. The event ID is the change number and the event name is the kind of change. Reconnecting clients resume after the Last-Event-ID header, new clients start after the latest change unless after is given, after=0 replays the whole feed. Resuming from a change older than the retained ones fails with 410 Gone.
+// @Tags songs
+// @Produce  text/event-stream
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param after query int false "Stream changes with a greater ID"
+// @Param Last-Event-ID header int false "ID of the last change received, takes precedence over after"
+// @Success 200 {object} dto.SongChangeEvent "Data of each event"
+// @Failure 400 {object} dto.Problem "Bad request error with a detailed message"
+// @Failure 403 {object} dto.Problem "The caller's role does not allow the operation"
+// @Failure 410 {object} dto.Problem "Changes after the given ID were removed by retention"
+// @Router /songs/changes [get]
+func (h *ChangeFeedHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	cursor, err := changeFeedCursor(r)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Pinning "latest" to an ID here lets a failing lookup answer with a
+	// problem, once the event stream starts the client only sees a disconnect
+	if cursor == changeFeedLatestCursor {
+		if cursor, err = h.changeFeed.LastID(r.Context()); err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+	} else {
+		purged, err := h.changeFeed.PurgedThrough(r.Context())
+		if err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+		if cursor < purged {
+			utils.WriteError(w, r, http.StatusGone, fmt.Sprintf("changes up to %d were removed by retention, reload the songs and follow the feed from the latest change", purged))
+			return
+		}
+	}
+
+	controller := http.NewResponseController(w)
+	// Streams outlive the server write timeout
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", changeFeedRetryMillis); err != nil {
+		return
+	}
+	_ = controller.Flush()
+
+	poll := time.NewTicker(h.cfg.GetPollInterval())
+	defer poll.Stop()
+	heartbeat := time.NewTicker(h.cfg.GetHeartbeatInterval())
+	defer heartbeat.Stop()
+
+	for {
+		events, err := h.changeFeed.ListAfter(r.Context(), cursor, changeFeedBatchSize)
+		if err != nil {
+			// Headers are gone, closing the stream makes the client reconnect
+			return
+		}
+
+		for _, event := range events {
+			data, err := json.Marshal(dto.SongChangeEventFromModel(event))
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Change, data); err != nil {
+				return
+			}
+			cursor = event.ID
+		}
+		if len(events) > 0 {
+			if err := controller.Flush(); err != nil {
+				return
+			}
+		}
+
+		// A full batch means more changes are waiting
+		if len(events) == changeFeedBatchSize {
+			continue
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			if err := controller.Flush(); err != nil {
+				return
+			}
+		case <-poll.C:
+		}
+	}
+}
+
+// changeFeedCursor reads where the stream starts, changeFeedLatestCursor
+// means after the latest change.
+func changeFeedCursor(r *http.Request) (int64, error) {
+	value := r.Header.Get(lastEventIDHeader)
+	if value == "" {
+		value = r.URL.Query().Get(changeFeedAfterParam)
+	}
+	if value == "" {
+		return changeFeedLatestCursor, nil
+	}
+
+	cursor, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || cursor < 0 {
+		return 0, errors.New("invalid change ID: " + value)
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,70 @@
+package handlers_test
+
+import (
+	"context"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/transport/http/handlers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type changeFeedConfig struct{}
+
+func (changeFeedConfig) GetPollInterval() time.Duration      { return time.Hour }
+func (changeFeedConfig) GetHeartbeatInterval() time.Duration { return time.Hour }
+
+// purgedChangeFeed has removed every change up to purgedThrough.
+type purgedChangeFeed struct {
+	service.ChangeFeedService
+	purgedThrough int64
+}
+
+func (f purgedChangeFeed) PurgedThrough(context.Context) (int64, error) {
+	return f.purgedThrough, nil
+}
+
+func (f purgedChangeFeed) LastID(context.Context) (int64, error) {
+	return f.purgedThrough + 10, nil
+}
+
+func (f purgedChangeFeed) ListAfter(context.Context, int64, int) ([]*model.SongChangeEvent, error) {
+	return nil, nil
+}
+
+func TestChangeFeedStreamRejectsPurgedCursor(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		header string
+		want   int
+	}{
+		{name: "latest", want: http.StatusOK},
+		{name: "after the purged changes", query: "?after=5", want: http.StatusOK},
+		{name: "after a purged change", query: "?after=4", want: http.StatusGone},
+		{name: "whole feed", query: "?after=0", want: http.StatusGone},
+		{name: "reconnect after a purged change", header: "3", want: http.StatusGone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := handlers.NewChangeFeedHandler(purgedChangeFeed{purgedThrough: 5}, changeFeedConfig{})
+
+			// A canceled request ends the stream after the first poll
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			req := httptest.NewRequest(http.MethodGet, "/songs/changes"+tt.query, nil).WithContext(ctx)
+			if tt.header != "" {
+				req.Header.Set("Last-Event-ID", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.Stream(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
@@ -1,12 +1,74 @@
 package handlers
 
 import (
+	"github.com/orungrau/em_song_library/internal/health"
+	"github.com/orungrau/em_song_library/internal/transport/http/dto"
 	"github.com/orungrau/em_song_library/internal/transport/http/utils"
 	"net/http"
 )
 
-func HealthCheck(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
+type HealthHandler struct {
+	registry *health.Registry
+}
+
+func NewHealthHandler(registry *health.Registry) *HealthHandler {
+	return &HealthHandler{
+		registry: registry,
+	}
+}
 
+// Legacy godoc
+// @Summary Health check
+// @Description Plain text readiness kept for old probes, use /health/ready instead.
+// @Tags health
+// @Produce  plain
+// @Success 200 {string} string "OK"
+// @Failure 503 {string} string "Service Unavailable"
+// @Deprecated
+// @Router /health [get]
+func (h *HealthHandler) Legacy(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Deprecation", "true")
+	w.Header().Set("Link", `</health/ready>; rel="successor-version"`)
+
+	if h.registry.Ready(r.Context()).Status != health.StatusUp {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		utils.Write(w, []byte(http.StatusText(http.StatusServiceUnavailable)))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
 	utils.Write(w, []byte("OK"))
 }
+
+// Live godoc
+// @Summary Liveness probe
+// @Description Report that the process is running. Dependencies are not checked.
+// @Tags health
+// @Produce  json
+// @Success 200 {object} dto.HealthReport "The service is alive"
+// @Router /health/live [get]
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	utils.WriteJson(w, dto.HealthReport{
+		Status: health.StatusUp,
+		Checks: make([]dto.HealthCheck, 0),
+	}, http.StatusOK)
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description Check the dependencies required to serve traffic. Fails while the service is shutting down.
+// @Tags health
+// @Produce  json
+// @Success 200 {object} dto.HealthReport "The service is ready"
+// @Failure 503 {object} dto.HealthReport "A required dependency is unavailable"
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	report := h.registry.Ready(r.Context())
+
+	status := http.StatusOK
+	if report.Status != health.StatusUp {
+		status = http.StatusServiceUnavailable
+	}
+
+	utils.WriteJson(w, dto.HealthReportFromModel(report), status)
+}
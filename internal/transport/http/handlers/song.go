@@ -2,25 +2,36 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/gorilla/schema"
 	"github.com/orungrau/em_song_library/internal/domain/model"
 	"github.com/orungrau/em_song_library/internal/domain/service"
 	"github.com/orungrau/em_song_library/internal/transport/http/dto"
 	"github.com/orungrau/em_song_library/internal/transport/http/utils"
 	"net/http"
+	"time"
 )
 
+type SongHandlerConfig interface {
+	GetLastModifiedSettle() time.Duration
+}
+
 type SongHandler struct {
 	validate    *validator.Validate
 	decoder     *schema.Decoder
 	songService service.SongService
+	changeFeed  service.ChangeFeedService
+	cfg         SongHandlerConfig
 }
 
-func NewSongHandler(songService service.SongService) *SongHandler {
-	validate := validator.New()
+// NewSongHandler dates listings by the latest change of the feed, so caches
+// can revalidate them with If-Modified-Since.
+func NewSongHandler(songService service.SongService, changeFeed service.ChangeFeedService, cfg SongHandlerConfig) *SongHandler {
+	validate := utils.NewValidator()
 	decoder := schema.NewDecoder()
 
 	decoder.IgnoreUnknownKeys(true)
@@ -30,6 +41,8 @@ func NewSongHandler(songService service.SongService) *SongHandler {
 		decoder:     decoder,
 		validate:    validate,
 		songService: songService,
+		changeFeed:  changeFeed,
+		cfg:         cfg,
 	}
 }
 
@@ -39,6 +52,8 @@ func NewSongHandler(songService service.SongService) *SongHandler {
 // @Tags songs
 // @Accept  json
 // @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Param release_date_from query int64 false "Filter songs by release date from (Unix timestamp)"
 // @Param release_date_to query int64 false "Filter songs by release date to (Unix timestamp)"
 // @Param title query string false "Filter songs by title"
@@ -47,20 +62,35 @@ func NewSongHandler(songService service.SongService) *SongHandler {
 // @Param group query string false "Filter songs by group"
 // @Param page query int false "Page number (default: 0)"
 // @Param page_size query int false "Page size (default: 10)"
+// @Param fields query string false "Comma separated fields to return, e.g. id,title,group"
+// @Param include query string false "Comma separated fields to add to the default ones, e.g. text"
+// @Param If-Modified-Since header string false "Date of the cached copy, answered with 304 when it is current"
 // @Success 200 {object} dto.SongList "A paginated list of songs"
-// @Failure 400 {object} dto.Status "Bad request error with a detailed message"
+// @Success 304 "The cached copy is current"
+// @Failure 400 {object} dto.Problem "Bad request error with a detailed message"
+// @Failure 403 {object} dto.Problem "The caller's role does not allow the operation"
 // @Router /songs [get]
 func (h *SongHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
-		utils.WriteErrorJson(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+		utils.WriteError(w, r, http.StatusBadRequest, "Failed to parse form")
 		return
 	}
 
 	var filter dto.SongFilter
 	err = h.decoder.Decode(&filter, r.Form)
 	if err != nil {
-		utils.WriteErrorJson(w, "Failed to decode filter: "+err.Error(), http.StatusBadRequest)
+		utils.WriteError(w, r, http.StatusBadRequest, "Failed to decode filter")
+		return
+	}
+
+	fields, err := filter.SongFields.Resolve(dto.ListSongFields)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if h.checkListNotModified(w, r) {
 		return
 	}
 
@@ -73,16 +103,17 @@ func (h *SongHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		ReleaseDateTo:   filter.ReleaseDateTo,
 		Page:            filter.Page,
 		PageSize:        filter.PageSize,
+		Fields:          fields,
 	})
 	if err != nil {
-		utils.WriteErrorJson(w, "Internal Server Error", http.StatusInternalServerError)
+		writeServiceError(w, r, err)
 		return
 	}
 
 	songsDto := make([]dto.Song, 0)
 
 	for _, i := range songs {
-		songsDto = append(songsDto, dto.SongFromModel(i))
+		songsDto = append(songsDto, dto.SongFromModel(i, fields))
 	}
 
 	response := dto.SongList{
@@ -94,37 +125,194 @@ func (h *SongHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJson(w, response, http.StatusOK)
 }
 
+// Search godoc
+// @Summary Fuzzy search songs
+// @Description Search songs by title and group tolerating typos and ignoring diacritics. Results are ordered by similarity score.
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param q query string true "Search query"
+// @Param limit query int false "Maximum number of results (default: 10, max: 100)"
+// @Param fields query string false "Comma separated fields to return, e.g. id,title,group"
+// @Param include query string false "Comma separated fields to add to the default ones, e.g. text"
+// @Param If-Modified-Since header string false "Date of the cached copy, answered with 304 when it is current"
+// @Success 200 {object} dto.SongSearchResult "Songs ordered by similarity score"
+// @Success 304 "The cached copy is current"
+// @Failure 400 {object} dto.Problem "Bad request error with a detailed message"
+// @Failure 403 {object} dto.Problem "The caller's role does not allow the operation"
+// @Router /songs/search [get]
+func (h *SongHandler) Search(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, "Failed to parse form")
+		return
+	}
+
+	var search dto.SongSearch
+	err = h.decoder.Decode(&search, r.Form)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, "Failed to decode search")
+		return
+	}
+
+	if err := h.validate.Struct(search); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	fields, err := search.SongFields.Resolve(dto.ListSongFields)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if h.checkListNotModified(w, r) {
+		return
+	}
+
+	matches, err := h.songService.Search(r.Context(), search.Query, search.Limit)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	matchesDto := make([]dto.SongMatch, 0)
+
+	for _, i := range matches {
+		matchesDto = append(matchesDto, dto.SongMatch{
+			Song:  dto.SongFromModel(i.Song, fields),
+			Score: i.Score,
+		})
+	}
+
+	response := dto.SongSearchResult{
+		Data:  matchesDto,
+		Query: search.Query,
+	}
+
+	utils.WriteJson(w, response, http.StatusOK)
+}
+
+// Suggest godoc
+// @Summary Autocomplete suggestions
+// @Description Get distinct titles or groups starting with the prefix, ranked by how many songs share them.
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param prefix query string true "Prefix typed by the user"
+// @Param field query string false "Field to complete" Enums(title, group) default(title)
+// @Param limit query int false "Maximum number of suggestions (default: 10, max: 50)"
+// @Success 200 {object} dto.SuggestionList "Completions ranked by frequency"
+// @Failure 400 {object} dto.Problem "Bad request error with a detailed message"
+// @Failure 403 {object} dto.Problem "The caller's role does not allow the operation"
+// @Router /suggest [get]
+func (h *SongHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, "Failed to parse form")
+		return
+	}
+
+	var query dto.SuggestQuery
+	err = h.decoder.Decode(&query, r.Form)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, "Failed to decode suggest query")
+		return
+	}
+
+	if err := h.validate.Struct(query); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	suggestions, err := h.songService.Suggest(r.Context(), model.SuggestField(query.Field), query.Prefix, query.Limit)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	suggestionsDto := make([]dto.Suggestion, 0)
+
+	for _, i := range suggestions {
+		suggestionsDto = append(suggestionsDto, dto.Suggestion{
+			Value:     i.Value,
+			Frequency: i.Frequency,
+		})
+	}
+
+	response := dto.SuggestionList{
+		Data:   suggestionsDto,
+		Field:  query.Field,
+		Prefix: query.Prefix,
+	}
+
+	utils.WriteJson(w, response, http.StatusOK)
+}
+
 // Get godoc
 // @Summary Get a single song
 // @Description Retrieve details of a specific song by its ID.
 // @Tags songs
 // @Accept  json
 // @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Param songId path string true "ID of the song to retrieve"
+// @Param fields query string false "Comma separated fields to return, all of them by default"
+// @Param If-Modified-Since header string false "Date of the cached copy, answered with 304 when it is current"
 // @Success 200 {object} dto.Song "Details of the requested song"
-// @Failure 400 {object} dto.Status "Bad request error with a detailed message"
+// @Success 304 "The cached copy is current"
+// @Failure 400 {object} dto.Problem "Bad request error with a detailed message"
+// @Failure 403 {object} dto.Problem "The caller's role does not allow the operation"
 // @Router /songs/{songId} [get]
 func (h *SongHandler) Get(w http.ResponseWriter, r *http.Request) {
-	songId := chi.URLParam(r, "songId")
+	songId, ok := songID(w, r)
+	if !ok {
+		return
+	}
+
+	fields, err := dto.SongFields{Fields: queryParam(r, "fields"), Include: queryParam(r, "include")}.Resolve(nil)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	song, err := h.songService.Get(r.Context(), songId)
 	if err != nil {
-		utils.WriteErrorJson(w, "Internal Server Error", http.StatusInternalServerError)
+		writeServiceError(w, r, err)
 		return
 	}
 	if song == nil {
-		utils.WriteErrorJson(w, "Not found", http.StatusNotFound)
+		utils.WriteError(w, r, http.StatusNotFound, "Not found")
+		return
+	}
+
+	lastModified := song.UpdatedAt
+	if lastModified == nil {
+		lastModified = song.CreatedAt
+	}
+	if utils.CheckNotModified(w, r, lastModified, h.cfg.GetLastModifiedSettle()) {
 		return
 	}
 
-	createdSong := dto.Song{
-		ID:          *song.ID,
-		Title:       *song.Title,
-		Group:       *song.Group,
-		ReleaseDate: *song.ReleaseDate,
+	utils.WriteJson(w, dto.SongFromModel(song, fields), http.StatusOK)
+}
+
+// checkListNotModified dates a listing by the latest change of the library.
+// The time is read before the songs, so a change made meanwhile is never
+// hidden behind an older date.
+func (h *SongHandler) checkListNotModified(w http.ResponseWriter, r *http.Request) bool {
+	lastChangedAt, err := h.changeFeed.LastChangedAt(r.Context())
+	if err != nil {
+		// The listing is still served, only without a validator
+		return false
 	}
 
-	utils.WriteJson(w, createdSong, http.StatusOK)
+	return utils.CheckNotModified(w, r, lastChangedAt, h.cfg.GetLastModifiedSettle())
 }
 
 // Create godoc
@@ -133,34 +321,34 @@ func (h *SongHandler) Get(w http.ResponseWriter, r *http.Request) {
 // @Tags songs
 // @Accept  json
 // @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Param song body dto.CreateSong true "Details of the song to create"
 // @Success 201 {object} dto.Song "The created song"
-// @Failure 400 {object} dto.Status "Bad request error with a detailed message"
+// @Failure 400 {object} dto.Problem "Bad request error with a detailed message"
+// @Failure 403 {object} dto.Problem "The caller's role does not allow the operation"
+// @Failure 409 {object} dto.Problem "A song with the same group and title already exists"
 // @Router /songs [post]
 func (h *SongHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var createDTO dto.CreateSong
 
 	if err := json.NewDecoder(r.Body).Decode(&createDTO); err != nil {
-		utils.WriteErrorJson(w, fmt.Sprintf("Invalid JSON format: %v", err), http.StatusBadRequest)
+		utils.WriteError(w, r, http.StatusBadRequest, "Invalid JSON format")
 		return
 	}
 
 	if err := h.validate.Struct(createDTO); err != nil {
-		utils.WriteErrorJson(w, err.Error(), http.StatusBadRequest)
+		utils.WriteValidationError(w, r, err)
 		return
 	}
 
-	createdSong, err := h.songService.Create(r.Context(), model.Song{
-		Title:       &createDTO.Title,
-		Group:       &createDTO.Group,
-		ReleaseDate: &createDTO.ReleaseDate.Time,
-	})
+	createdSong, err := h.songService.Create(r.Context(), createDTO.ToModel())
 	if err != nil {
-		utils.WriteErrorJson(w, "Internal Server Error", http.StatusInternalServerError)
+		writeServiceError(w, r, err)
 		return
 	}
 
-	utils.WriteJson(w, dto.SongFromModel(createdSong), http.StatusCreated)
+	utils.WriteJson(w, dto.SongFromModel(createdSong, nil), http.StatusCreated)
 }
 
 // Update godoc
@@ -169,22 +357,29 @@ func (h *SongHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Tags songs
 // @Accept  json
 // @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Param songId path string true "ID of the song to update"
-// @Param song body dto.Song true "Updated details of the song"
+// @Param song body dto.UpdateSong true "Updated details of the song"
 // @Success 200 {object} dto.Song "The updated song"
-// @Failure 400 {object} dto.Status "Bad request error with a detailed message"
+// @Failure 400 {object} dto.Problem "Bad request error with a detailed message"
+// @Failure 403 {object} dto.Problem "The caller's role does not allow the operation"
+// @Failure 409 {object} dto.Problem "A song with the same group and title already exists"
 // @Router /songs/{songId} [patch]
 func (h *SongHandler) Update(w http.ResponseWriter, r *http.Request) {
-	songId := chi.URLParam(r, "songId")
-	var updateDTO dto.Song
+	songId, ok := songID(w, r)
+	if !ok {
+		return
+	}
+	var updateDTO dto.UpdateSong
 
 	if err := json.NewDecoder(r.Body).Decode(&updateDTO); err != nil {
-		utils.WriteErrorJson(w, fmt.Sprintf("Invalid JSON format: %v", err), http.StatusBadRequest)
+		utils.WriteError(w, r, http.StatusBadRequest, "Invalid JSON format")
 		return
 	}
 
 	if err := h.validate.Struct(updateDTO); err != nil {
-		utils.WriteErrorJson(w, err.Error(), http.StatusBadRequest)
+		utils.WriteValidationError(w, r, err)
 		return
 	}
 
@@ -198,11 +393,11 @@ func (h *SongHandler) Update(w http.ResponseWriter, r *http.Request) {
 	})
 
 	if err != nil {
-		utils.WriteErrorJson(w, err.Error(), http.StatusBadRequest)
+		writeServiceError(w, r, err)
 		return
 	}
 
-	utils.WriteJson(w, dto.SongFromModel(updatedSong), http.StatusOK)
+	utils.WriteJson(w, dto.SongFromModel(updatedSong, nil), http.StatusOK)
 }
 
 // Delete godoc
@@ -211,16 +406,22 @@ func (h *SongHandler) Update(w http.ResponseWriter, r *http.Request) {
 // @Tags songs
 // @Accept  json
 // @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Param songId path string true "ID of the song to delete"
 // @Success 200 {object} dto.Status "Confirmation of successful deletion"
-// @Failure 400 {object} dto.Status "Bad request error with a detailed message"
+// @Failure 400 {object} dto.Problem "Bad request error with a detailed message"
+// @Failure 403 {object} dto.Problem "The caller's role does not allow the operation"
 // @Router /songs/{songId} [delete]
 func (h *SongHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	songId := chi.URLParam(r, "songId")
+	songId, ok := songID(w, r)
+	if !ok {
+		return
+	}
 
 	err := h.songService.Delete(r.Context(), songId)
 	if err != nil {
-		utils.WriteErrorJson(w, err.Error(), http.StatusBadRequest)
+		writeServiceError(w, r, err)
 		return
 	}
 
@@ -231,3 +432,57 @@ func (h *SongHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	utils.WriteJson(w, status, http.StatusOK)
 }
+
+func queryParam(r *http.Request, name string) *string {
+	if !r.URL.Query().Has(name) {
+		return nil
+	}
+
+	value := r.URL.Query().Get(name)
+	return &value
+}
+
+// songID reads the song ID path parameter, rejecting values that are not UUIDs
+// before they reach the database.
+func songID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	songId := chi.URLParam(r, "songId")
+
+	if _, err := uuid.Parse(songId); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, "Invalid song ID")
+		return "", false
+	}
+
+	return songId, true
+}
+
+// writeServiceError maps song service errors to problems without exposing
+// storage details to clients.
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		forbidden *service.ForbiddenError
+		conflict  *service.SongConflictError
+	)
+
+	switch {
+	case errors.As(err, &forbidden):
+		utils.WriteProblem(w, r, dto.Problem{
+			Type:   dto.ProblemTypeForbidden,
+			Title:  http.StatusText(http.StatusForbidden),
+			Status: http.StatusForbidden,
+			Detail: forbidden.Reason,
+		})
+	case errors.As(err, &conflict):
+		w.Header().Set("Location", "/songs/"+conflict.ExistingID)
+		utils.WriteProblem(w, r, dto.Problem{
+			Type:       dto.ProblemTypeConflict,
+			Title:      http.StatusText(http.StatusConflict),
+			Status:     http.StatusConflict,
+			Detail:     conflict.Error(),
+			ExistingID: conflict.ExistingID,
+		})
+	case errors.Is(err, service.ErrSongNotFound):
+		utils.WriteError(w, r, http.StatusNotFound, err.Error())
+	default:
+		utils.WriteError(w, r, http.StatusInternalServerError, "Internal Server Error")
+	}
+}
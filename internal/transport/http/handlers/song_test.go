@@ -0,0 +1,97 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/transport/http/dto"
+	"github.com/orungrau/em_song_library/internal/transport/http/handlers"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type songHandlerConfig struct{}
+
+func (songHandlerConfig) GetLastModifiedSettle() time.Duration { return 0 }
+
+// rejectingSongService fails the test when a write gets past validation.
+type rejectingSongService struct {
+	service.SongService
+	t *testing.T
+}
+
+func (s rejectingSongService) Create(ctx context.Context, song model.Song) (*model.Song, error) {
+	s.t.Fatalf("Create() called with an invalid song: %+v", song)
+	return nil, nil
+}
+
+func TestSongHandlerCreateReportsFieldViolations(t *testing.T) {
+	tests := []struct {
+		name       string
+		language   string
+		body       string
+		violations map[string]dto.FieldViolation
+	}{
+		{
+			name: "missing fields",
+			body: `{}`,
+			violations: map[string]dto.FieldViolation{
+				"title":        {Field: "title", Rule: "required", Message: "title is a required field"},
+				"group":        {Field: "group", Rule: "required", Message: "group is a required field"},
+				"release_date": {Field: "release_date", Rule: "required", Message: "release_date is a required field"},
+			},
+		},
+		{
+			name: "too long",
+			body: `{"title": "` + strings.Repeat("a", 256) + `", "group": "Muse", "release_date": 1150000000}`,
+			violations: map[string]dto.FieldViolation{
+				"title": {Field: "title", Rule: "max", Message: "title must be a maximum of 255 characters in length"},
+			},
+		},
+		{
+			name:     "translated",
+			language: "ru-RU,ru;q=0.9",
+			body:     `{"group": "Muse", "release_date": 1150000000}`,
+			violations: map[string]dto.FieldViolation{
+				"title": {Field: "title", Rule: "required", Message: "title обязательное поле"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := handlers.NewSongHandler(rejectingSongService{t: t}, nil, songHandlerConfig{})
+
+			req := httptest.NewRequest(http.MethodPost, "/songs", strings.NewReader(tt.body))
+			if tt.language != "" {
+				req.Header.Set("Accept-Language", tt.language)
+			}
+			rec := httptest.NewRecorder()
+			handler.Create(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+
+			var problem dto.Problem
+			if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+				t.Fatal(err)
+			}
+			if problem.Type != dto.ProblemTypeValidation {
+				t.Errorf("type = %q, want %q", problem.Type, dto.ProblemTypeValidation)
+			}
+			if len(problem.Errors) != len(tt.violations) {
+				t.Fatalf("errors = %+v, want %d violations", problem.Errors, len(tt.violations))
+			}
+			for _, violation := range problem.Errors {
+				if want := tt.violations[violation.Field]; violation != want {
+					t.Errorf("violation = %+v, want %+v", violation, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/transport/http/dto"
+	"github.com/orungrau/em_song_library/internal/transport/http/utils"
+	"net/http"
+	"strconv"
+)
+
+type WebhookHandler struct {
+	validate       *validator.Validate
+	webhookService service.WebhookService
+}
+
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		validate:       utils.NewValidator(),
+		webhookService: webhookService,
+	}
+}
+
+// List godoc
+// @Summary List webhooks
+// @Description List all webhook subscriptions including deleted ones. Secrets are never returned.
+// @Tags webhooks
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {object} dto.WebhookList "Webhook subscriptions"
+// @Failure 401 {object} dto.Problem "Authentication required"
+// @Failure 403 {object} dto.Problem "Missing scope"
+// @Router /webhooks [get]
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.webhookService.ListSubscriptions(r.Context())
+	if err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	webhooksDto := make([]dto.Webhook, 0)
+
+	for _, i := range subscriptions {
+		webhooksDto = append(webhooksDto, dto.WebhookFromModel(i))
+	}
+
+	utils.WriteJson(w, dto.WebhookList{Data: webhooksDto}, http.StatusOK)
+}
+
+// Create godoc
+// @Summary Create a webhook
+// @Description Subscribe a URL to song events. Deliveries are signed with the secret, which is returned only once.
+// @Tags webhooks
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param webhook body dto.CreateWebhook true "URL, events and optional secret of the webhook"
+// @Success 201 {object} dto.CreatedWebhook "The created webhook with its secret"
+// @Failure 400 {object} dto.Problem "Bad request error with a detailed message"
+// @Failure 401 {object} dto.Problem "Authentication required"
+// @Failure 403 {object} dto.Problem "Missing scope"
+// @Router /webhooks [post]
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var createDTO dto.CreateWebhook
+
+	if err := json.NewDecoder(r.Body).Decode(&createDTO); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, "Invalid JSON format")
+		return
+	}
+
+	if err := h.validate.Struct(createDTO); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	subscription, err := h.webhookService.CreateSubscription(r.Context(), createDTO.URL, createDTO.Events, createDTO.Secret)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	utils.WriteJson(w, dto.CreatedWebhook{
+		Webhook: dto.WebhookFromModel(subscription),
+		Secret:  subscription.Secret,
+	}, http.StatusCreated)
+}
+
+// Delete godoc
+// @Summary Delete a webhook
+// @Description Stop delivering events to a webhook. Its pending deliveries are moved to dead letters, the delivery log is kept.
+// @Tags webhooks
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param webhookId path string true "ID of the webhook to delete"
+// @Success 200 {object} dto.Status "Confirmation of deletion"
+// @Failure 400 {object} dto.Problem "Bad request error with a detailed message"
+// @Failure 404 {object} dto.Problem "Webhook not found or already deleted"
+// @Router /webhooks/{webhookId} [delete]
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	webhookId := chi.URLParam(r, "webhookId")
+
+	if _, err := uuid.Parse(webhookId); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	err := h.webhookService.DeleteSubscription(r.Context(), webhookId)
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			utils.WriteError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.WriteError(w, r, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	utils.WriteJson(w, dto.Status{
+		Error:   false,
+		Message: fmt.Sprintf("Webhook deleted with id: %s", webhookId),
+	}, http.StatusOK)
+}
+
+// ListDeliveries godoc
+// @Summary List webhook deliveries
+// @Description List the deliveries of a webhook in ID order with their attempts and last error. Page with after_id.
+// @Tags webhooks
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param webhookId path string true "ID of the webhook"
+// @Param status query string false "Delivery status" Enums(pending, delivered, dead)
+// @Param after_id query int false "Return deliveries with a greater ID"
+// @Param limit query int false "Maximum number of deliveries" default(100)
+// @Success 200 {object} dto.WebhookDeliveryList "Webhook deliveries"
+// @Failure 400 {object} dto.Problem "Bad request error with a detailed message"
+// @Failure 401 {object} dto.Problem "Authentication required"
+// @Failure 403 {object} dto.Problem "Missing scope"
+// @Router /webhooks/{webhookId}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	webhookId := chi.URLParam(r, "webhookId")
+
+	if _, err := uuid.Parse(webhookId); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	filter := model.WebhookDeliveryFilter{SubscriptionID: webhookId}
+	query := r.URL.Query()
+
+	if value := query.Get("status"); value != "" {
+		status := model.WebhookDeliveryStatus(value)
+		switch status {
+		case model.WebhookDeliveryPending, model.WebhookDeliveryDelivered, model.WebhookDeliveryDead:
+			filter.Status = &status
+		default:
+			utils.WriteError(w, r, http.StatusBadRequest, "Invalid status: "+value)
+			return
+		}
+	}
+
+	if value := query.Get("after_id"); value != "" {
+		afterID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || afterID < 0 {
+			utils.WriteError(w, r, http.StatusBadRequest, "Invalid after_id: "+value)
+			return
+		}
+		filter.AfterID = afterID
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			utils.WriteError(w, r, http.StatusBadRequest, "Invalid limit: "+value)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(r.Context(), filter)
+	if err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	deliveriesDto := make([]dto.WebhookDelivery, 0)
+
+	for _, i := range deliveries {
+		deliveriesDto = append(deliveriesDto, dto.WebhookDeliveryFromModel(i))
+	}
+
+	list := dto.WebhookDeliveryList{Data: deliveriesDto}
+	// A short page is the last one
+	if filter.Limit > 0 && len(deliveries) == filter.Limit {
+		list.NextAfterID = &deliveries[len(deliveries)-1].ID
+	}
+
+	utils.WriteJson(w, list, http.StatusOK)
+}
+
+// Redeliver godoc
+// @Summary Redeliver a webhook delivery
+// @Description Queue a delivered or dead delivery again with fresh attempts. The payload and event ID stay the same.
+// @Tags webhooks
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param webhookId path string true "ID of the webhook"
+// @Param deliveryId path int true "ID of the delivery"
+// @Success 202 {object} dto.Status "Confirmation of queuing"
+// @Failure 400 {object} dto.Problem "Bad request error with a detailed message"
+// @Failure 404 {object} dto.Problem "Delivery not found or still pending"
+// @Router /webhooks/{webhookId}/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	webhookId := chi.URLParam(r, "webhookId")
+
+	if _, err := uuid.Parse(webhookId); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	deliveryId, err := strconv.ParseInt(chi.URLParam(r, "deliveryId"), 10, 64)
+	if err != nil || deliveryId < 1 {
+		utils.WriteError(w, r, http.StatusBadRequest, "Invalid delivery ID")
+		return
+	}
+
+	err = h.webhookService.Redeliver(r.Context(), webhookId, deliveryId)
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookDeliveryPending) {
+			utils.WriteError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.WriteError(w, r, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	utils.WriteJson(w, dto.Status{
+		Error:   false,
+		Message: fmt.Sprintf("Webhook delivery queued with id: %d", deliveryId),
+	}, http.StatusAccepted)
+}
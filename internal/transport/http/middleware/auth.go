@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/orungrau/em_song_library/internal/auth"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/domain/service"
+	"github.com/orungrau/em_song_library/internal/transport/http/dto"
+	"github.com/orungrau/em_song_library/internal/transport/http/utils"
+	"net/http"
+	"strings"
+)
+
+const (
+	APIKeyHeader = "X-API-Key"
+	bearerPrefix = "Bearer "
+)
+
+type AuthMiddleware struct {
+	authenticator *auth.Authenticator
+}
+
+func NewAuthMiddleware(authenticator *auth.Authenticator) *AuthMiddleware {
+	return &AuthMiddleware{
+		authenticator: authenticator,
+	}
+}
+
+// Middleware resolves the principal of the request. With authentication
+// disabled every request runs as the anonymous principal.
+func (m *AuthMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		creds := auth.Credentials{APIKey: r.Header.Get(APIKeyHeader)}
+		if authorization := r.Header.Get("Authorization"); strings.HasPrefix(authorization, bearerPrefix) {
+			creds.BearerToken = strings.TrimPrefix(authorization, bearerPrefix)
+		}
+
+		principal, err := m.authenticator.Authenticate(r.Context(), creds)
+		switch {
+		case err == nil:
+		case errors.Is(err, auth.ErrInvalidToken):
+			m.challenge(w)
+			utils.WriteError(w, r, http.StatusUnauthorized, "Invalid bearer token")
+			return
+		case errors.Is(err, service.ErrInvalidAPIKey):
+			m.challenge(w)
+			utils.WriteError(w, r, http.StatusUnauthorized, err.Error())
+			return
+		case errors.Is(err, auth.ErrMissingCredentials):
+			m.challenge(w)
+			utils.WriteError(w, r, http.StatusUnauthorized, "Authentication required")
+			return
+		default:
+			utils.WriteError(w, r, http.StatusInternalServerError, "Internal Server Error")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(actor.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+func (m *AuthMiddleware) challenge(w http.ResponseWriter) {
+	w.Header().Add("WWW-Authenticate", APIKeyHeader)
+	if m.authenticator.BearerEnabled() {
+		w.Header().Add("WWW-Authenticate", "Bearer")
+	}
+}
+
+// RequireRole rejects requests whose principal does not hold role.
+func RequireRole(role actor.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := actor.PrincipalFromContext(r.Context())
+			if !ok {
+				utils.WriteError(w, r, http.StatusUnauthorized, "Authentication required")
+				return
+			}
+
+			if !principal.HasRole(role) {
+				utils.WriteProblem(w, r, dto.Problem{
+					Type:   dto.ProblemTypeForbidden,
+					Title:  http.StatusText(http.StatusForbidden),
+					Status: http.StatusForbidden,
+					Detail: "Missing role: " + string(role),
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	CacheVisibilityPublic  = "public"
+	CacheVisibilityPrivate = "private"
+	cacheControlNoStore    = "no-store"
+)
+
+type CacheControlConfig interface {
+	GetVisibility() string
+	GetSongMaxAge() time.Duration
+	GetListMaxAge() time.Duration
+}
+
+// CacheControlMiddleware sets the Cache-Control policy of routes. Successful
+// responses get the policy of their route, errors are never stored so a
+// denied or failed request is not replayed by a cache.
+type CacheControlMiddleware struct {
+	song string
+	list string
+}
+
+func NewCacheControlMiddleware(cfg CacheControlConfig) (*CacheControlMiddleware, error) {
+	visibility := cfg.GetVisibility()
+	if visibility != CacheVisibilityPublic && visibility != CacheVisibilityPrivate {
+		return nil, fmt.Errorf("invalid cache visibility: %s", visibility)
+	}
+
+	return &CacheControlMiddleware{
+		song: cacheDirective(visibility, cfg.GetSongMaxAge()),
+		list: cacheDirective(visibility, cfg.GetListMaxAge()),
+	}, nil
+}
+
+func cacheDirective(visibility string, maxAge time.Duration) string {
+	if maxAge <= 0 {
+		// Caches may keep the response but must revalidate it every time
+		return visibility + ", no-cache"
+	}
+
+	return fmt.Sprintf("%s, max-age=%d", visibility, int(maxAge.Seconds()))
+}
+
+// Song applies the policy of single songs.
+func (m *CacheControlMiddleware) Song(next http.Handler) http.Handler {
+	return cacheControl(m.song, next)
+}
+
+// List applies the policy of song listings, searches and suggestions.
+func (m *CacheControlMiddleware) List(next http.Handler) http.Handler {
+	return cacheControl(m.list, next)
+}
+
+// NoStore keeps responses out of every cache, it is the default of the API.
+func (m *CacheControlMiddleware) NoStore(next http.Handler) http.Handler {
+	return cacheControl(cacheControlNoStore, next)
+}
+
+func cacheControl(directive string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if directive != cacheControlNoStore {
+			// Responses depend on the credentials when authentication is enabled
+			w.Header().Add("Vary", "Authorization, X-API-Key")
+		}
+
+		next.ServeHTTP(&cacheControlResponseWriter{ResponseWriter: w, directive: directive}, r)
+	})
+}
+
+type cacheControlResponseWriter struct {
+	http.ResponseWriter
+	directive   string
+	wroteHeader bool
+}
+
+func (w *cacheControlResponseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+
+		switch {
+		case statusCode >= http.StatusBadRequest:
+			w.Header().Set("Cache-Control", cacheControlNoStore)
+		case w.Header().Get("Cache-Control") == "":
+			// Handlers such as event streams set their own policy
+			w.Header().Set("Cache-Control", w.directive)
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *cacheControlResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *cacheControlResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *cacheControlResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
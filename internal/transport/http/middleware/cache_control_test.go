@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type cacheControlConfig struct {
+	visibility string
+	songMaxAge time.Duration
+	listMaxAge time.Duration
+}
+
+func (c cacheControlConfig) GetVisibility() string        { return c.visibility }
+func (c cacheControlConfig) GetSongMaxAge() time.Duration { return c.songMaxAge }
+func (c cacheControlConfig) GetListMaxAge() time.Duration { return c.listMaxAge }
+
+func TestCacheControlMiddleware(t *testing.T) {
+	m, err := NewCacheControlMiddleware(cacheControlConfig{
+		visibility: CacheVisibilityPrivate,
+		songMaxAge: time.Minute,
+		listMaxAge: 0,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		middleware func(http.Handler) http.Handler
+		status     int
+		preset     string
+		want       string
+		wantVary   bool
+	}{
+		{name: "song", middleware: m.Song, status: http.StatusOK, want: "private, max-age=60", wantVary: true},
+		{name: "list revalidates", middleware: m.List, status: http.StatusOK, want: "private, no-cache", wantVary: true},
+		{name: "not modified", middleware: m.Song, status: http.StatusNotModified, want: "private, max-age=60", wantVary: true},
+		{name: "no store", middleware: m.NoStore, status: http.StatusOK, want: "no-store"},
+		{name: "error", middleware: m.Song, status: http.StatusNotFound, want: "no-store", wantVary: true},
+		{name: "error with a preset policy", middleware: m.List, status: http.StatusForbidden, preset: "no-cache", want: "no-store", wantVary: true},
+		{name: "handler policy kept", middleware: m.Song, status: http.StatusOK, preset: "no-cache", want: "no-cache", wantVary: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := tt.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.preset != "" {
+					w.Header().Set("Cache-Control", tt.preset)
+				}
+				w.WriteHeader(tt.status)
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/songs", nil))
+
+			if got := rec.Header().Get("Cache-Control"); got != tt.want {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.want)
+			}
+			if vary := rec.Header().Get("Vary") != ""; vary != tt.wantVary {
+				t.Errorf("Vary = %q, want set %v", rec.Header().Get("Vary"), tt.wantVary)
+			}
+		})
+	}
+}
+
+func TestCacheControlMiddlewareAppliesOnImplicitStatus(t *testing.T) {
+	m, err := NewCacheControlMiddleware(cacheControlConfig{visibility: CacheVisibilityPublic, songMaxAge: 30 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := m.Song(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{}"))
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/songs/1", nil))
+
+	if got := rec.Header().Get("Cache-Control"); got != "public, max-age=30" {
+		t.Errorf("Cache-Control = %q, want public, max-age=30", got)
+	}
+}
+
+func TestCacheControlMiddlewareRejectsUnknownVisibility(t *testing.T) {
+	if _, err := NewCacheControlMiddleware(cacheControlConfig{visibility: "shared"}); err == nil {
+		t.Error("NewCacheControlMiddleware() accepted visibility shared")
+	}
+}
@@ -0,0 +1,291 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	EncodingGzip   = "gzip"
+	EncodingBrotli = "br"
+	EncodingZstd   = "zstd"
+	encodingAny    = "*"
+)
+
+type CompressionConfig interface {
+	GetEnabled() bool
+	GetMinSize() int
+	// GetEncodings lists the supported encodings in order of preference.
+	GetEncodings() []string
+}
+
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// CompressionMiddleware compresses responses with the encoding the client
+// prefers among gzip, br and zstd. Responses smaller than the minimum size,
+// already encoded ones and event streams are sent as is.
+type CompressionMiddleware struct {
+	enabled   bool
+	minSize   int
+	encodings []string
+	pools     map[string]*sync.Pool
+}
+
+func NewCompressionMiddleware(cfg CompressionConfig) (*CompressionMiddleware, error) {
+	pools := map[string]*sync.Pool{
+		EncodingGzip: {New: func() any {
+			return gzip.NewWriter(io.Discard)
+		}},
+		EncodingBrotli: {New: func() any {
+			return brotli.NewWriterLevel(io.Discard, brotli.DefaultCompression)
+		}},
+		EncodingZstd: {New: func() any {
+			// The default options cannot fail
+			w, _ := zstd.NewWriter(io.Discard, zstd.WithEncoderConcurrency(1))
+			return w
+		}},
+	}
+
+	for _, encoding := range cfg.GetEncodings() {
+		if _, ok := pools[encoding]; !ok {
+			return nil, fmt.Errorf("unsupported encoding: %s", encoding)
+		}
+	}
+
+	return &CompressionMiddleware{
+		enabled:   cfg.GetEnabled(),
+		minSize:   cfg.GetMinSize(),
+		encodings: cfg.GetEncodings(),
+		pools:     pools,
+	}, nil
+}
+
+func (m *CompressionMiddleware) Middleware(next http.Handler) http.Handler {
+	if !m.enabled || len(m.encodings) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), m.encodings)
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressResponseWriter{
+			ResponseWriter: w,
+			encoding:       encoding,
+			pool:           m.pools[encoding],
+			minSize:        m.minSize,
+			statusCode:     http.StatusOK,
+		}
+		defer cw.Close()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks the encoding with the highest quality value in
+// Accept-Encoding, ties go to the server preference. An empty result means
+// identity.
+func negotiateEncoding(header string, supported []string) string {
+	if header == "" {
+		return ""
+	}
+
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		qualities[name] = quality
+	}
+
+	best, bestQuality := "", 0.0
+	for _, encoding := range supported {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality, ok = qualities[encodingAny]
+		}
+		if ok && quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+
+	return best
+}
+
+// compressResponseWriter buffers the start of the body to decide whether it
+// is worth compressing, then streams through the encoder.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding    string
+	pool        *sync.Pool
+	minSize     int
+	statusCode  int
+	wroteHeader bool
+	decided     bool
+	buffer      bytes.Buffer
+	encoder     encoder
+}
+
+func (w *compressResponseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.statusCode = statusCode
+
+	// Informational and bodiless responses are sent right away
+	if statusCode < http.StatusOK || statusCode == http.StatusNoContent || statusCode == http.StatusNotModified {
+		w.decided = true
+		w.ResponseWriter.WriteHeader(statusCode)
+	}
+}
+
+func (w *compressResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.decided {
+		if !w.compressible() {
+			w.start(false)
+		} else {
+			w.buffer.Write(p)
+			if w.buffer.Len() < w.minSize {
+				return len(p), nil
+			}
+			if err := w.start(true); err != nil {
+				return 0, err
+			}
+			return len(p), nil
+		}
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Write(p)
+	}
+
+	return w.ResponseWriter.Write(p)
+}
+
+// compressible reports whether the response may be compressed judging by
+// its headers.
+func (w *compressResponseWriter) compressible() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	// Event streams are flushed event by event and must not wait for a buffer
+	if mediaType == "text/event-stream" {
+		return false
+	}
+
+	return mediaType == "" ||
+		strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "json") ||
+		strings.HasSuffix(mediaType, "xml") ||
+		slices.Contains([]string{"application/javascript", "application/x-ndjson", "image/svg+xml"}, mediaType)
+}
+
+// start sends the header and the buffered body, compressed or not.
+func (w *compressResponseWriter) start(compress bool) error {
+	w.decided = true
+
+	if compress {
+		header := w.Header()
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		// A strong validator of the identity body does not match the encoded one
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+
+		w.encoder = w.pool.Get().(encoder)
+		w.encoder.Reset(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(w.statusCode)
+
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(w.buffer.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.buffer.Bytes())
+	}
+	w.buffer.Reset()
+
+	return err
+}
+
+// Flush sends what was written so far, compressing it when the buffer is
+// already large enough to be worth it.
+func (w *compressResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.decided {
+		_ = w.start(w.compressible() && w.buffer.Len() >= w.minSize)
+	}
+	if w.encoder != nil {
+		_ = w.encoder.Flush()
+	}
+
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Close finishes the response once the handler returned.
+func (w *compressResponseWriter) Close() {
+	if !w.decided {
+		if !w.wroteHeader {
+			// The handler wrote nothing, let the server send its default response
+			return
+		}
+		_ = w.start(false)
+	}
+
+	if w.encoder != nil {
+		_ = w.encoder.Close()
+		w.encoder.Reset(io.Discard)
+		w.pool.Put(w.encoder)
+		w.encoder = nil
+	}
+}
+
+func (w *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type compressionConfig struct {
+	enabled   bool
+	minSize   int
+	encodings []string
+}
+
+func (c compressionConfig) GetEnabled() bool       { return c.enabled }
+func (c compressionConfig) GetMinSize() int        { return c.minSize }
+func (c compressionConfig) GetEncodings() []string { return c.encodings }
+
+func newTestCompression(t *testing.T, cfg compressionConfig) *CompressionMiddleware {
+	t.Helper()
+
+	m, err := NewCompressionMiddleware(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return m
+}
+
+func decode(t *testing.T, encoding string, body io.Reader) string {
+	t.Helper()
+
+	var reader io.Reader
+	switch encoding {
+	case EncodingGzip:
+		r, err := gzip.NewReader(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = r
+	case EncodingBrotli:
+		reader = brotli.NewReader(body)
+	case EncodingZstd:
+		r, err := zstd.NewReader(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		reader = r
+	default:
+		reader = body
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	supported := []string{EncodingZstd, EncodingBrotli, EncodingGzip}
+
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: ""},
+		{header: "identity", want: ""},
+		{header: "gzip", want: EncodingGzip},
+		{header: "gzip, br", want: EncodingBrotli},
+		{header: "gzip;q=1.0, br;q=0.5", want: EncodingGzip},
+		{header: "GZIP", want: EncodingGzip},
+		{header: "*", want: EncodingZstd},
+		{header: "*;q=0.1, gzip;q=0.5", want: EncodingGzip},
+		{header: "gzip;q=0", want: ""},
+		{header: "gzip;q=bad, br", want: EncodingBrotli},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := negotiateEncoding(tt.header, supported); got != tt.want {
+				t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompressionMiddleware(t *testing.T) {
+	large := strings.Repeat(`{"title":"Hysteria"}`, 100)
+
+	tests := []struct {
+		name           string
+		method         string
+		acceptEncoding string
+		contentType    string
+		header         http.Header
+		status         int
+		body           string
+		wantEncoding   string
+		wantETag       string
+	}{
+		{name: "gzip", acceptEncoding: "gzip", body: large, wantEncoding: EncodingGzip},
+		{name: "brotli", acceptEncoding: "br", body: large, wantEncoding: EncodingBrotli},
+		{name: "zstd", acceptEncoding: "zstd, gzip", body: large, wantEncoding: EncodingZstd},
+		{name: "not accepted", body: large},
+		{name: "below the minimum size", acceptEncoding: "gzip", body: `{"title":"Hysteria"}`},
+		{name: "head request", method: http.MethodHead, acceptEncoding: "gzip"},
+		{name: "event stream", acceptEncoding: "gzip", contentType: "text/event-stream", body: large},
+		{name: "binary", acceptEncoding: "gzip", contentType: "image/png", body: large},
+		{
+			name:           "already encoded",
+			acceptEncoding: "gzip",
+			header:         http.Header{"Content-Encoding": {"br"}},
+			body:           large,
+			wantEncoding:   "br",
+		},
+		{
+			name:           "strong ETag weakened",
+			acceptEncoding: "gzip",
+			header:         http.Header{"Etag": {`"v1"`}},
+			body:           large,
+			wantEncoding:   EncodingGzip,
+			wantETag:       `W/"v1"`,
+		},
+		{name: "error status", acceptEncoding: "gzip", status: http.StatusNotFound, body: large, wantEncoding: EncodingGzip},
+	}
+
+	m := newTestCompression(t, compressionConfig{
+		enabled:   true,
+		minSize:   256,
+		encodings: []string{EncodingZstd, EncodingBrotli, EncodingGzip},
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for name, values := range tt.header {
+					w.Header()[name] = values
+				}
+				contentType := tt.contentType
+				if contentType == "" {
+					contentType = "application/json"
+				}
+				w.Header().Set("Content-Type", contentType)
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				_, _ = io.WriteString(w, tt.body)
+			}))
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, "/songs", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			wantStatus := tt.status
+			if wantStatus == 0 {
+				wantStatus = http.StatusOK
+			}
+			if rec.Code != wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, wantStatus)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", got)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if tt.wantETag != "" && rec.Header().Get("ETag") != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", rec.Header().Get("ETag"), tt.wantETag)
+			}
+
+			// A body the handler encoded itself is passed through untouched
+			encoding := tt.wantEncoding
+			if tt.header.Get("Content-Encoding") != "" {
+				encoding = ""
+			}
+			if got := decode(t, encoding, rec.Body); got != tt.body {
+				t.Errorf("body = %.40q, want %.40q", got, tt.body)
+			}
+		})
+	}
+}
+
+func TestCompressionMiddlewareFlushesSmallStreams(t *testing.T) {
+	m := newTestCompression(t, compressionConfig{enabled: true, minSize: 1024, encodings: []string{EncodingGzip}})
+	handler := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "partial")
+		_ = http.NewResponseController(w).Flush()
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/songs", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if !rec.Flushed {
+		t.Error("response not flushed")
+	}
+	if rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != "partial" {
+		t.Errorf("body = %q (%q), want it sent uncompressed", rec.Body, rec.Header().Get("Content-Encoding"))
+	}
+}
+
+func TestCompressionMiddlewareConfig(t *testing.T) {
+	if _, err := NewCompressionMiddleware(compressionConfig{enabled: true, encodings: []string{"deflate"}}); err == nil {
+		t.Error("NewCompressionMiddleware() accepted an unsupported encoding")
+	}
+
+	m := newTestCompression(t, compressionConfig{enabled: false, encodings: []string{EncodingGzip}})
+	handler := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, strings.Repeat("a", 1024))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/songs", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Header().Get("Content-Encoding") != "" {
+		t.Error("disabled middleware compressed the response")
+	}
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/transport/http/utils"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const corsAnyOrigin = "*"
+
+// corsWildcard matches the subdomains of an origin, like https://*.example.com.
+type corsWildcard struct {
+	scheme string
+	suffix string
+}
+
+func (w corsWildcard) matches(origin string) bool {
+	return strings.HasPrefix(origin, w.scheme) &&
+		strings.HasSuffix(origin, w.suffix) &&
+		len(origin) > len(w.scheme)+len(w.suffix)
+}
+
+type CORSConfig interface {
+	// GetAllowedOrigins lists origins such as https://app.example.com, a
+	// *. prefix on the host matches subdomains and * matches every origin.
+	GetAllowedOrigins() []string
+	GetAllowedMethods() []string
+	GetAllowedHeaders() []string
+	GetExposedHeaders() []string
+	GetAllowCredentials() bool
+	GetMaxAge() time.Duration
+}
+
+// CORSMiddleware lets browsers on the allowed origins call the API. It
+// answers preflight requests itself, before routing and authentication,
+// since browsers send them without credentials.
+type CORSMiddleware struct {
+	anyOrigin        bool
+	origins          []string
+	wildcards        []corsWildcard
+	allowedMethods   string
+	allowedHeaders   string
+	exposedHeaders   string
+	allowCredentials bool
+	maxAge           string
+}
+
+func NewCORSMiddleware(cfg CORSConfig) (*CORSMiddleware, error) {
+	m := &CORSMiddleware{
+		allowedMethods:   strings.ToUpper(strings.Join(cfg.GetAllowedMethods(), ", ")),
+		allowedHeaders:   strings.Join(cfg.GetAllowedHeaders(), ", "),
+		exposedHeaders:   strings.Join(cfg.GetExposedHeaders(), ", "),
+		allowCredentials: cfg.GetAllowCredentials(),
+		maxAge:           strconv.Itoa(int(cfg.GetMaxAge().Seconds())),
+	}
+
+	for _, origin := range cfg.GetAllowedOrigins() {
+		origin = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+		switch {
+		case origin == "":
+		case origin == corsAnyOrigin:
+			m.anyOrigin = true
+		case strings.Contains(origin, "://*."):
+			scheme, host, _ := strings.Cut(origin, "://*.")
+			m.wildcards = append(m.wildcards, corsWildcard{scheme: scheme + "://", suffix: "." + host})
+		case strings.Contains(origin, "*"):
+			return nil, fmt.Errorf("invalid CORS origin: %s", origin)
+		default:
+			m.origins = append(m.origins, origin)
+		}
+	}
+
+	// Browsers reject credentials with a wildcard origin, listing origins is required
+	if m.anyOrigin && m.allowCredentials {
+		return nil, errors.New("CORS credentials cannot be allowed for every origin")
+	}
+
+	return m, nil
+}
+
+func (m *CORSMiddleware) Middleware(next http.Handler) http.Handler {
+	if !m.anyOrigin && len(m.origins) == 0 && len(m.wildcards) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		header := w.Header()
+		header.Add("Vary", "Origin")
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !m.allowed(origin) {
+			if preflight {
+				utils.WriteError(w, r, http.StatusForbidden, "Origin not allowed: "+origin)
+				return
+			}
+			// Browsers hide the response from the page without CORS headers
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if m.anyOrigin {
+			header.Set("Access-Control-Allow-Origin", corsAnyOrigin)
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if m.allowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if m.exposedHeaders != "" {
+				header.Set("Access-Control-Expose-Headers", m.exposedHeaders)
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header.Set("Access-Control-Allow-Methods", m.allowedMethods)
+		if m.allowedHeaders != "" {
+			header.Set("Access-Control-Allow-Headers", m.allowedHeaders)
+		}
+		header.Set("Access-Control-Max-Age", m.maxAge)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (m *CORSMiddleware) allowed(origin string) bool {
+	if m.anyOrigin {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	if slices.Contains(m.origins, origin) {
+		return true
+	}
+
+	return slices.ContainsFunc(m.wildcards, func(w corsWildcard) bool {
+		return w.matches(origin)
+	})
+}
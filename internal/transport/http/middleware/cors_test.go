@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type corsConfig struct {
+	origins     []string
+	credentials bool
+}
+
+func (c corsConfig) GetAllowedOrigins() []string { return c.origins }
+func (c corsConfig) GetAllowedMethods() []string { return []string{"get", "post"} }
+func (c corsConfig) GetAllowedHeaders() []string { return []string{"Content-Type", "X-API-Key"} }
+func (c corsConfig) GetExposedHeaders() []string { return []string{"ETag"} }
+func (c corsConfig) GetAllowCredentials() bool   { return c.credentials }
+func (c corsConfig) GetMaxAge() time.Duration    { return 10 * time.Minute }
+
+func newTestCORS(t *testing.T, cfg corsConfig) http.Handler {
+	t.Helper()
+
+	m, err := NewCORSMiddleware(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestCORSWildcardMatches(t *testing.T) {
+	wildcard := corsWildcard{scheme: "https://", suffix: ".example.com"}
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{origin: "https://app.example.com", want: true},
+		{origin: "https://a.b.example.com", want: true},
+		{origin: "https://example.com", want: false},
+		{origin: "https://.example.com", want: false},
+		{origin: "http://app.example.com", want: false},
+		{origin: "https://app.example.com.evil.org", want: false},
+		{origin: "https://appexample.com", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			if got := wildcard.matches(tt.origin); got != tt.want {
+				t.Errorf("matches(%q) = %v, want %v", tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCORSMiddlewarePreflight(t *testing.T) {
+	handler := newTestCORS(t, corsConfig{
+		origins:     []string{"https://app.example.com/", "https://*.Example.org"},
+		credentials: true,
+	})
+
+	tests := []struct {
+		name       string
+		origin     string
+		wantStatus int
+		wantOrigin string
+	}{
+		{name: "listed origin", origin: "https://app.example.com", wantStatus: http.StatusNoContent, wantOrigin: "https://app.example.com"},
+		{name: "origin case", origin: "https://APP.example.com", wantStatus: http.StatusNoContent, wantOrigin: "https://APP.example.com"},
+		{name: "wildcard origin", origin: "https://admin.example.org", wantStatus: http.StatusNoContent, wantOrigin: "https://admin.example.org"},
+		{name: "unknown origin", origin: "https://evil.org", wantStatus: http.StatusForbidden},
+		{name: "wildcard parent", origin: "https://example.org", wantStatus: http.StatusForbidden},
+		{name: "other scheme", origin: "http://app.example.com", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, "/songs", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			header := rec.Header()
+			if got := header.Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if tt.wantStatus != http.StatusNoContent {
+				return
+			}
+			if got := header.Get("Access-Control-Allow-Methods"); got != "GET, POST" {
+				t.Errorf("Access-Control-Allow-Methods = %q, want GET, POST", got)
+			}
+			if got := header.Get("Access-Control-Allow-Headers"); got != "Content-Type, X-API-Key" {
+				t.Errorf("Access-Control-Allow-Headers = %q", got)
+			}
+			if got := header.Get("Access-Control-Max-Age"); got != "600" {
+				t.Errorf("Access-Control-Max-Age = %q, want 600", got)
+			}
+			if got := header.Get("Access-Control-Allow-Credentials"); got != "true" {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want true", got)
+			}
+		})
+	}
+}
+
+func TestCORSMiddlewareActualRequests(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     []string
+		origin      string
+		wantOrigin  string
+		wantExposed string
+	}{
+		{name: "allowed", origins: []string{"https://app.example.com"}, origin: "https://app.example.com", wantOrigin: "https://app.example.com", wantExposed: "ETag"},
+		{name: "any origin", origins: []string{"*"}, origin: "https://app.example.com", wantOrigin: "*", wantExposed: "ETag"},
+		{name: "denied origin reaches the handler", origins: []string{"https://app.example.com"}, origin: "https://evil.org"},
+		{name: "same origin", origins: []string{"https://app.example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestCORS(t, corsConfig{origins: tt.origins})
+
+			req := httptest.NewRequest(http.MethodGet, "/songs", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Errorf("status = %d, want the handler response", rec.Code)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if got := rec.Header().Get("Access-Control-Expose-Headers"); got != tt.wantExposed {
+				t.Errorf("Access-Control-Expose-Headers = %q, want %q", got, tt.wantExposed)
+			}
+			if got := rec.Header().Get("Vary"); got != "Origin" {
+				t.Errorf("Vary = %q, want Origin", got)
+			}
+		})
+	}
+}
+
+func TestNewCORSMiddlewareRejectsInvalidOrigins(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  corsConfig
+	}{
+		{name: "wildcard inside the host", cfg: corsConfig{origins: []string{"https://app.*.example.com"}}},
+		{name: "credentials for every origin", cfg: corsConfig{origins: []string{"*"}, credentials: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCORSMiddleware(tt.cfg); err == nil {
+				t.Error("NewCORSMiddleware() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/orungrau/em_song_library/internal/transport/http/utils"
+	"net/http"
+)
+
+// ErrorFormatMiddleware switches error responses to the legacy
+// {error, message} shape when legacy is set.
+func ErrorFormatMiddleware(legacy bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !legacy {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(utils.WithLegacyErrors(r.Context())))
+		})
+	}
+}
@@ -1,35 +1,47 @@
 package middleware
 
 import (
-	"context"
-	"github.com/google/uuid"
+	"github.com/go-chi/chi/v5"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/transport/http/utils"
+	"github.com/orungrau/em_song_library/pkg/logger"
 	"github.com/rs/zerolog"
 	"net/http"
 	"time"
 )
 
+const unmatchedRoute = "unmatched"
+
+type RequestObserver interface {
+	ObserveHTTPRequest(method, route string, status int, duration time.Duration)
+}
+
 type LoggerMiddleware struct {
-	log zerolog.Logger
+	log      zerolog.Logger
+	observer RequestObserver
 }
 
-func NewLoggerMiddleware(log zerolog.Logger) *LoggerMiddleware {
-	return &LoggerMiddleware{log: log.With().Str("module", "http-transport").Logger()}
+func NewLoggerMiddleware(log zerolog.Logger, observer RequestObserver) *LoggerMiddleware {
+	return &LoggerMiddleware{
+		log:      log.With().Str("module", "http-transport").Logger(),
+		observer: observer,
+	}
 }
 
 func (m *LoggerMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
-		ctx := r.Context()
-		// TODO: Get span-id from headers
-		ctx = context.WithValue(ctx, "span-id", uuid.New().String())
-		ctx = context.WithValue(ctx, "duration", start)
+		ctx := logger.WithStartTime(r.Context(), start)
 		r = r.WithContext(ctx)
 
-		logger := m.log.With().
+		logContext := m.log.With().
 			Str("method", r.Method).
-			Str("url", r.URL.String()).
-			Logger()
+			Str("url", r.URL.String())
+		if request, ok := actor.RequestFromContext(ctx); ok {
+			logContext = logContext.Str("request_id", request.ID)
+		}
+		logger := logContext.Logger()
 
 		lrw := &loggingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 
@@ -39,10 +51,13 @@ func (m *LoggerMiddleware) Middleware(next http.Handler) http.Handler {
 					Interface("panic", rec).
 					Int("status", http.StatusInternalServerError).
 					Msg("Recovered from panic")
-				http.Error(lrw, "InternalServerError", http.StatusInternalServerError)
+				utils.WriteError(lrw, r, http.StatusInternalServerError, "")
+				m.observe(r, http.StatusInternalServerError, start)
 				return
 			}
 
+			m.observe(r, lrw.statusCode, start)
+
 			if lrw.statusCode >= 400 {
 				logger.Error().
 					Ctx(ctx).
@@ -60,6 +75,20 @@ func (m *LoggerMiddleware) Middleware(next http.Handler) http.Handler {
 	})
 }
 
+func (m *LoggerMiddleware) observe(r *http.Request, status int, start time.Time) {
+	if m.observer == nil {
+		return
+	}
+
+	// The route pattern is complete only once the router has matched the request.
+	route := unmatchedRoute
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		route = rctx.RoutePattern()
+	}
+
+	m.observer.ObserveHTTPRequest(r.Method, route, status, time.Since(start))
+}
+
 type loggingResponseWriter struct {
 	http.ResponseWriter
 	statusCode int
@@ -69,3 +98,15 @@ func (lrw *loggingResponseWriter) WriteHeader(statusCode int) {
 	lrw.statusCode = statusCode
 	lrw.ResponseWriter.WriteHeader(statusCode)
 }
+
+// Flush lets streaming handlers push data through the wrapper.
+func (lrw *loggingResponseWriter) Flush() {
+	if flusher, ok := lrw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the wrapped writer to http.ResponseController.
+func (lrw *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return lrw.ResponseWriter
+}
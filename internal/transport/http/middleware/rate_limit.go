@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/transport/http/dto"
+	"github.com/orungrau/em_song_library/internal/transport/http/utils"
+	"github.com/orungrau/em_song_library/pkg/ratelimit"
+	"github.com/rs/zerolog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ipRateLimitRule      = "ip"
+	defaultRateLimitRule = "default"
+)
+
+type RateLimitConfig interface {
+	GetEnabled() bool
+	GetIPLimit() string
+	GetDefaultLimit() string
+	GetRouteLimits() map[string]string
+}
+
+// RateLimitMiddleware throttles clients with a token bucket per client and
+// rule. Routes listed in the config, as "METHOD /pattern", get their own
+// bucket, every other route shares the default one. A separate bucket per IP
+// address is checked before authentication.
+type RateLimitMiddleware struct {
+	log          zerolog.Logger
+	store        ratelimit.Store
+	enabled      bool
+	ipLimit      ratelimit.Limit
+	defaultLimit ratelimit.Limit
+	routeLimits  map[string]ratelimit.Limit
+}
+
+func NewRateLimitMiddleware(log zerolog.Logger, store ratelimit.Store, cfg RateLimitConfig) (*RateLimitMiddleware, error) {
+	ipLimit, err := ratelimit.ParseLimit(cfg.GetIPLimit())
+	if err != nil {
+		return nil, fmt.Errorf("ip: %w", err)
+	}
+
+	defaultLimit, err := ratelimit.ParseLimit(cfg.GetDefaultLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	routeLimits := make(map[string]ratelimit.Limit)
+	for route, value := range cfg.GetRouteLimits() {
+		limit, err := ratelimit.ParseLimit(value)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", route, err)
+		}
+		routeLimits[strings.Join(strings.Fields(route), " ")] = limit
+	}
+
+	return &RateLimitMiddleware{
+		log:          log.With().Str("module", "rate-limit").Logger(),
+		store:        store,
+		enabled:      cfg.GetEnabled(),
+		ipLimit:      ipLimit,
+		defaultLimit: defaultLimit,
+		routeLimits:  routeLimits,
+	}, nil
+}
+
+// PerIP throttles callers by address before their credentials are checked,
+// so failed authentication attempts count against the limit too.
+func (m *RateLimitMiddleware) PerIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.enabled || m.allow(w, r, ipRateLimitRule, ipKey(r), m.ipLimit) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+func (m *RateLimitMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rule, limit := m.rule(r)
+		if m.allow(w, r, rule, clientKey(r), limit) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// allow takes a token from the bucket of the client under rule, it writes the
+// rate limit headers and answers with 429 when the bucket is empty.
+func (m *RateLimitMiddleware) allow(w http.ResponseWriter, r *http.Request, rule, client string, limit ratelimit.Limit) bool {
+	result, err := m.store.Allow(r.Context(), rule+"|"+client, limit)
+	if err != nil {
+		// An unavailable store must not take the API down with it
+		m.log.Error().Err(err).Ctx(r.Context()).Msg("Rate limit store failed, allowing request")
+		return true
+	}
+
+	w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, ceilSeconds(limit.Period)))
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(limit.Requests))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+	if !result.Allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(max(1, ceilSeconds(result.RetryAfter))))
+		utils.WriteProblem(w, r, dto.Problem{
+			Type:   dto.ProblemTypeRateLimit,
+			Title:  http.StatusText(http.StatusTooManyRequests),
+			Status: http.StatusTooManyRequests,
+			Detail: fmt.Sprintf("Rate limit of %d requests per %s exceeded", limit.Requests, limit.Period),
+		})
+		return false
+	}
+
+	return true
+}
+
+// rule resolves the route pattern up front, middlewares of mounted routers
+// run before the router has matched the full pattern.
+func (m *RateLimitMiddleware) rule(r *http.Request) (string, ratelimit.Limit) {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.Routes != nil {
+		match := chi.NewRouteContext()
+		if rctx.Routes.Match(match, r.Method, r.URL.Path) {
+			route := r.Method + " " + strings.TrimSuffix(match.RoutePattern(), "/")
+			if route == r.Method+" " {
+				route += "/"
+			}
+			if limit, ok := m.routeLimits[route]; ok {
+				return route, limit
+			}
+		}
+	}
+
+	return defaultRateLimitRule, m.defaultLimit
+}
+
+// clientKey identifies authenticated clients by principal and everyone else
+// by IP address.
+func clientKey(r *http.Request) string {
+	if principal, ok := actor.PrincipalFromContext(r.Context()); ok && principal.Method != actor.MethodAnonymous {
+		return principal.Method + ":" + principal.ID
+	}
+
+	return ipKey(r)
+}
+
+func ipKey(r *http.Request) string {
+	if request, ok := actor.RequestFromContext(r.Context()); ok {
+		return "ip:" + request.IP
+	}
+
+	return "ip:" + r.RemoteAddr
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/google/uuid"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"net"
+	"net/http"
+)
+
+const (
+	RequestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+)
+
+// RequestMiddleware assigns every request an ID, reusing the one sent by the
+// client or a proxy when present, and records it with the client IP.
+func RequestMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		ctx := actor.WithRequest(r.Context(), &actor.Request{ID: id, IP: ip})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -4,31 +4,124 @@ import (
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	_ "github.com/orungrau/em_song_library/docs"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/metrics"
 	"github.com/orungrau/em_song_library/internal/transport/http/handlers"
 	"github.com/orungrau/em_song_library/internal/transport/http/middleware"
+	"github.com/orungrau/em_song_library/internal/transport/http/utils"
 	"github.com/rs/zerolog"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"net/http"
 )
 
-func NewRouter(log zerolog.Logger, songHandler *handlers.SongHandler, address string) http.Handler {
+type Handlers struct {
+	Song       *handlers.SongHandler
+	Admin      *handlers.AdminHandler
+	Health     *handlers.HealthHandler
+	APIKey     *handlers.APIKeyHandler
+	Audit      *handlers.AuditHandler
+	ChangeFeed *handlers.ChangeFeedHandler
+	Webhook    *handlers.WebhookHandler
+	// GraphQL serves /graphql, it resolves songs with the same service as Song.
+	GraphQL http.Handler
+}
+
+type RouterConfig interface {
+	GetAddress() string
+	GetLegacyErrors() bool
+}
+
+func NewRouter(
+	log zerolog.Logger,
+	h Handlers,
+	auth *middleware.AuthMiddleware,
+	rateLimit *middleware.RateLimitMiddleware,
+	compression *middleware.CompressionMiddleware,
+	cacheControl *middleware.CacheControlMiddleware,
+	cors *middleware.CORSMiddleware,
+	securityHeaders *middleware.SecurityHeadersMiddleware,
+	appMetrics *metrics.Metrics,
+	cfg RouterConfig,
+) http.Handler {
+	address := cfg.GetAddress()
+
 	r := chi.NewRouter()
 
-	r.Use(middleware.NewLoggerMiddleware(log).Middleware)
+	r.Use(middleware.RequestMiddleware)
+	r.Use(middleware.ErrorFormatMiddleware(cfg.GetLegacyErrors()))
+	r.Use(middleware.TracingMiddleware)
+	r.Use(middleware.NewLoggerMiddleware(log, appMetrics).Middleware)
+	r.Use(securityHeaders.Middleware)
+	// Preflight requests are answered before routing and authentication
+	r.Use(cors.Middleware)
+	r.Use(compression.Middleware)
+
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		utils.WriteError(w, r, http.StatusNotFound, "")
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		utils.WriteError(w, r, http.StatusMethodNotAllowed, "")
+	})
+
+	r.Get("/health", h.Health.Legacy)
+	r.Get("/health/live", h.Health.Live)
+	r.Get("/health/ready", h.Health.Ready)
+
+	r.Group(func(r chi.Router) {
+		r.Use(rateLimit.PerIP)
+		r.Use(auth.Middleware)
+		r.Use(rateLimit.Middleware)
+		r.Use(cacheControl.NoStore)
+
+		// Song operations are authorized by the service policy
+		r.With(cacheControl.List).Get("/suggest", h.Song.Suggest)
+		r.Post("/graphql", h.GraphQL.ServeHTTP)
+
+		r.Route("/songs", func(r chi.Router) {
+			r.With(cacheControl.List).Get("/", h.Song.GetAll)
+			r.With(cacheControl.List).Get("/search", h.Song.Search)
+			r.Get("/changes", h.ChangeFeed.Stream)
+			r.With(cacheControl.Song).Get("/{songId}", h.Song.Get)
+			r.Post("/", h.Song.Create)
+			r.Patch("/{songId}", h.Song.Update)
+			r.Delete("/{songId}", h.Song.Delete)
+		})
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(middleware.RequireRole(actor.RoleAdmin))
+			r.Get("/migrations", h.Admin.MigrationStatus)
+		})
+
+		// Metrics reveal traffic and pool state, scrapers use an admin key
+		r.With(middleware.RequireRole(actor.RoleAdmin)).Handle("/metrics", appMetrics.Handler())
+
+		r.Route("/audit", func(r chi.Router) {
+			r.Use(middleware.RequireRole(actor.RoleAdmin))
+			r.Get("/", h.Audit.List)
+			r.Get("/export", h.Audit.Export)
+		})
 
-	r.Get("/health", handlers.HealthCheck)
+		r.Route("/api-keys", func(r chi.Router) {
+			r.Use(middleware.RequireRole(actor.RoleAdmin))
+			r.Get("/", h.APIKey.List)
+			r.Post("/", h.APIKey.Create)
+			r.Delete("/{keyId}", h.APIKey.Revoke)
+		})
 
-	r.Route("/songs", func(r chi.Router) {
-		r.Get("/", songHandler.GetAll)
-		r.Get("/{songId}", songHandler.Get)
-		r.Post("/", songHandler.Create)
-		r.Patch("/{songId}", songHandler.Update)
-		r.Delete("/{songId}", songHandler.Delete)
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(middleware.RequireRole(actor.RoleAdmin))
+			r.Get("/", h.Webhook.List)
+			r.Post("/", h.Webhook.Create)
+			r.Delete("/{webhookId}", h.Webhook.Delete)
+			r.Get("/{webhookId}/deliveries", h.Webhook.ListDeliveries)
+			r.Post("/{webhookId}/deliveries/{deliveryId}/redeliver", h.Webhook.Redeliver)
+		})
 	})
 
-	log.Debug().Msg(fmt.Sprintf("Swagger available at http://%s/swagger/index.html", address))
-	r.Get("/swagger/*", httpSwagger.Handler(
-		httpSwagger.URL(fmt.Sprintf("http://%s/swagger/doc.json", address)), // The url pointing to API definition
+	log.Debug().Msg(fmt.Sprintf("Swagger available at %s/swagger/index.html", address))
+	r.With(securityHeaders.Swagger).Get("/swagger/*", httpSwagger.Handler(
+		// Relative to the UI, so it works over HTTPS and behind proxies
+		httpSwagger.URL("doc.json"),
 	))
 
 	return r
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"net/http"
+	"time"
+)
+
+// CheckNotModified sets Last-Modified and answers 304 when the copy of the
+// client, dated by If-Modified-Since, is still current. It reports whether
+// the response was written.
+//
+// HTTP dates have a one second resolution and caches of other replicas may
+// lag behind the database, so a time within settle of now is not sent: a
+// later change in the same window would otherwise validate a stale copy.
+func CheckNotModified(w http.ResponseWriter, r *http.Request, lastModified *time.Time, settle time.Duration) bool {
+	if lastModified == nil || lastModified.IsZero() || time.Since(*lastModified) < max(settle, time.Second) {
+		return false
+	}
+
+	modified := lastModified.UTC().Truncate(time.Second)
+	w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || modified.After(since) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/orungrau/em_song_library/internal/domain/actor"
+	"github.com/orungrau/em_song_library/internal/transport/http/dto"
+	"net/http"
+)
+
+const ProblemContentType = "application/problem+json"
+
+type legacyErrorsKey struct{}
+
+// WithLegacyErrors makes errors of the request use the {error, message}
+// shape older clients expect instead of problem details.
+func WithLegacyErrors(ctx context.Context) context.Context {
+	return context.WithValue(ctx, legacyErrorsKey{}, true)
+}
+
+func legacyErrors(r *http.Request) bool {
+	legacy, _ := r.Context().Value(legacyErrorsKey{}).(bool)
+	return legacy
+}
+
+// WriteError responds with a problem of the generic type for status.
+func WriteError(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	WriteProblem(w, r, dto.Problem{
+		Type:   dto.ProblemTypeDefault,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	})
+}
+
+// WriteValidationError lists every violated rule with a message in the
+// language requested by Accept-Language.
+func WriteValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if legacyErrors(r) {
+		writeLegacy(w, dto.Status{Error: true, Message: err.Error()}, http.StatusBadRequest)
+		return
+	}
+
+	translator := translatorFor(r)
+	violations := make([]dto.FieldViolation, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		violations = append(violations, dto.FieldViolation{
+			Field:   fieldErr.Field(),
+			Rule:    fieldErr.Tag(),
+			Message: fieldErr.Translate(translator),
+		})
+	}
+
+	WriteProblem(w, r, dto.Problem{
+		Type:   dto.ProblemTypeValidation,
+		Title:  "Validation failed",
+		Status: http.StatusBadRequest,
+		Detail: fmt.Sprintf("%d field(s) of the request are invalid", len(violations)),
+		Errors: violations,
+	})
+}
+
+func WriteProblem(w http.ResponseWriter, r *http.Request, problem dto.Problem) {
+	if legacyErrors(r) {
+		message := problem.Detail
+		if message == "" {
+			message = problem.Title
+		}
+
+		if problem.ExistingID != "" {
+			writeLegacy(w, dto.ConflictStatus{Error: true, Message: message, ExistingID: problem.ExistingID}, problem.Status)
+			return
+		}
+		writeLegacy(w, dto.Status{Error: true, Message: message}, problem.Status)
+		return
+	}
+
+	if request, ok := actor.RequestFromContext(r.Context()); ok {
+		problem.Instance = request.ID
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+func writeLegacy(w http.ResponseWriter, body interface{}, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
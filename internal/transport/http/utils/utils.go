@@ -2,7 +2,6 @@ package utils
 
 import (
 	"encoding/json"
-	"github.com/orungrau/em_song_library/internal/transport/http/dto"
 	"net/http"
 )
 
@@ -21,17 +20,3 @@ func WriteJson(w http.ResponseWriter, data interface{}, status int) {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
-
-func WriteErrorJson(w http.ResponseWriter, message string, status int) {
-	_error := dto.Status{
-		Error:   true,
-		Message: message,
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	err := json.NewEncoder(w).Encode(_error)
-	if err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-	}
-}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ru"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	ruTranslations "github.com/go-playground/validator/v10/translations/ru"
+	"github.com/orungrau/em_song_library/internal/transport/http/dto"
+	"golang.org/x/text/language"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	translators = ut.New(en.New(), en.New(), ru.New())
+	languages   = language.NewMatcher([]language.Tag{language.English, language.Russian})
+)
+
+// NewValidator returns a validator reporting fields by their JSON or query
+// parameter names, with messages translated to every supported language.
+// Translations are registered on shared translators, so every handler gets
+// the same validator.
+var NewValidator = sync.OnceValue(newValidator)
+
+func newValidator() *validator.Validate {
+	validate := validator.New()
+
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "schema"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+	// Timestamps are validated as the time they hold, so required rejects zero
+	validate.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface().(dto.TimestampTime).Time
+	}, dto.TimestampTime{})
+
+	enTranslator, _ := translators.GetTranslator("en")
+	if err := enTranslations.RegisterDefaultTranslations(validate, enTranslator); err != nil {
+		panic(err)
+	}
+	ruTranslator, _ := translators.GetTranslator("ru")
+	if err := ruTranslations.RegisterDefaultTranslations(validate, ruTranslator); err != nil {
+		panic(err)
+	}
+
+	return validate
+}
+
+func translatorFor(r *http.Request) ut.Translator {
+	tag, _ := language.MatchStrings(languages, r.Header.Get("Accept-Language"))
+	base, _ := tag.Base()
+
+	translator, _ := translators.GetTranslator(base.String())
+	return translator
+}
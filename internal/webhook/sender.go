@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/internal/tracing"
+	"github.com/orungrau/em_song_library/pkg/webhook"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	userAgent        = "song-library-webhooks/1"
+	maxResponseBytes = 512
+)
+
+// HTTPSender posts signed deliveries to subscribers. Redirects are not
+// followed, a subscriber has to register the final URL.
+type HTTPSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender refuses to connect to loopback, private and other non-public
+// addresses unless allowPrivateNetworks is set, so subscriptions cannot reach
+// services inside the deployment.
+func NewHTTPSender(allowPrivateNetworks bool) *HTTPSender {
+	var base http.RoundTripper = newPublicTransport()
+	if allowPrivateNetworks {
+		base = http.DefaultTransport
+	}
+
+	return &HTTPSender{client: &http.Client{
+		Transport: tracing.NewTransport(base),
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+func (s *HTTPSender) Send(ctx context.Context, attempt *model.WebhookAttempt) (int, error) {
+	delivery := attempt.Delivery
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, attempt.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(webhook.IDHeader, delivery.EventID)
+	req.Header.Set(webhook.EventHeader, delivery.EventType)
+	req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(attempt.Secret, now, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+		return resp.StatusCode, fmt.Errorf("subscriber responded with %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,129 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"github.com/orungrau/em_song_library/internal/domain/model"
+	"github.com/orungrau/em_song_library/pkg/webhook"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func newAttempt(url string) *model.WebhookAttempt {
+	return &model.WebhookAttempt{
+		Delivery: &model.WebhookDelivery{
+			ID:        1,
+			EventID:   "event-1",
+			EventType: "song.created",
+			Payload:   []byte(`{"id":"event-1"}`),
+		},
+		URL:    url,
+		Secret: "secret",
+	}
+}
+
+func TestHTTPSenderSignsDelivery(t *testing.T) {
+	received := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(webhook.IDHeader) != "event-1" || r.Header.Get(webhook.EventHeader) != "song.created" {
+			received <- errors.New("missing event headers")
+			return
+		}
+		received <- webhook.Verify(
+			"secret",
+			r.Header.Get(webhook.TimestampHeader),
+			r.Header.Get(webhook.SignatureHeader),
+			body,
+			time.Minute,
+		)
+	}))
+	defer server.Close()
+
+	code, err := NewHTTPSender(true).Send(context.Background(), newAttempt(server.URL))
+	if err != nil || code != http.StatusOK {
+		t.Fatalf("Send() = %d, %v, want 200, nil", code, err)
+	}
+	if err := <-received; err != nil {
+		t.Fatalf("receiver rejected the delivery: %v", err)
+	}
+}
+
+func TestHTTPSenderReportsFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		wantCode int
+	}{
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			},
+			wantCode: http.StatusServiceUnavailable,
+		},
+		{
+			name: "redirect is not followed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "/elsewhere", http.StatusFound)
+			},
+			wantCode: http.StatusFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			code, err := NewHTTPSender(true).Send(context.Background(), newAttempt(server.URL))
+			if err == nil || code != tt.wantCode {
+				t.Fatalf("Send() = %d, %v, want %d and an error", code, err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestHTTPSenderBlocksPrivateNetworks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback receiver")
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPSender(false).Send(context.Background(), newAttempt(server.URL))
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("Send() error = %v, want %v", err, ErrBlockedAddress)
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	tests := map[string]bool{
+		"93.184.216.34":          true,
+		"2606:2800:220:1::1":     true,
+		"127.0.0.1":              false,
+		"10.1.2.3":               false,
+		"172.16.0.1":             false,
+		"192.168.1.1":            false,
+		"169.254.169.254":        false,
+		"100.64.0.1":             false,
+		"0.0.0.0":                false,
+		"::1":                    false,
+		"fd00::1":                false,
+		"fe80::1":                false,
+		"::ffff:127.0.0.1":       false,
+		"::ffff:169.254.169.254": false,
+		"64:ff9b::a9fe:a9fe":     false,
+		"255.255.255.255":        false,
+		"224.0.0.1":              false,
+	}
+
+	for address, want := range tests {
+		if got := isPublic(netip.MustParseAddr(address)); got != want {
+			t.Errorf("isPublic(%s) = %t, want %t", address, got, want)
+		}
+	}
+}
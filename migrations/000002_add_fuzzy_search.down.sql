@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_songs_title_trgm;
+DROP INDEX IF EXISTS idx_songs_group_trgm;
+
+DROP FUNCTION IF EXISTS immutable_unaccent(text);
+
+DROP EXTENSION IF EXISTS unaccent;
+DROP EXTENSION IF EXISTS pg_trgm;
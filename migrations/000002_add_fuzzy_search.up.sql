@@ -0,0 +1,18 @@
+-- The extensions are optional: without them search falls back to matching
+-- songs in memory, so a database where they cannot be installed still migrates.
+DO $$
+BEGIN
+    CREATE EXTENSION IF NOT EXISTS pg_trgm;
+    CREATE EXTENSION IF NOT EXISTS unaccent;
+
+    CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text
+        LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $fn$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $fn$;
+
+    CREATE INDEX idx_songs_title_trgm ON songs USING gin (immutable_unaccent(lower(title)) gin_trgm_ops);
+    CREATE INDEX idx_songs_group_trgm ON songs USING gin (immutable_unaccent(lower("group")) gin_trgm_ops);
+EXCEPTION
+    WHEN undefined_file OR insufficient_privilege THEN
+        RAISE NOTICE 'fuzzy search extensions are unavailable, search runs in memory: %', SQLERRM;
+END
+$$;
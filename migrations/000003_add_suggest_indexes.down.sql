@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_songs_title_prefix;
+DROP INDEX IF EXISTS idx_songs_group_prefix;
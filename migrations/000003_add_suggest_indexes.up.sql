@@ -0,0 +1,2 @@
+CREATE INDEX idx_songs_title_prefix ON songs (lower(title) text_pattern_ops) WHERE deleted_at IS NULL;
+CREATE INDEX idx_songs_group_prefix ON songs (lower("group") text_pattern_ops) WHERE deleted_at IS NULL;
@@ -0,0 +1,12 @@
+CREATE TABLE api_keys (
+                          id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                          name VARCHAR(255) NOT NULL,
+                          prefix VARCHAR(32) NOT NULL,
+                          key_hash BYTEA NOT NULL,
+                          scopes TEXT[] NOT NULL,
+                          created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                          last_used_at TIMESTAMP,
+                          revoked_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_api_keys_key_hash ON api_keys (key_hash);
@@ -0,0 +1,36 @@
+CREATE TABLE audit_log (
+                           id BIGSERIAL PRIMARY KEY,
+                           occurred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                           entity VARCHAR(64) NOT NULL,
+                           entity_id VARCHAR(255) NOT NULL,
+                           action VARCHAR(64) NOT NULL,
+                           actor_id VARCHAR(255) NOT NULL,
+                           actor_name VARCHAR(255) NOT NULL,
+                           actor_method VARCHAR(32) NOT NULL,
+                           request_id VARCHAR(255),
+                           ip VARCHAR(64),
+                           before JSONB,
+                           after JSONB,
+                           changes JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log (entity, entity_id, id);
+CREATE INDEX idx_audit_log_actor ON audit_log (actor_id, id);
+CREATE INDEX idx_audit_log_occurred_at ON audit_log (occurred_at);
+
+-- The audit log is append-only, rows can be neither changed nor removed.
+CREATE FUNCTION audit_log_reject_change() RETURNS trigger
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_reject_change();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_reject_change();
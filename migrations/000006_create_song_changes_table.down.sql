@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_song_changes_occurred_at;
+
+DROP TABLE IF EXISTS song_changes;
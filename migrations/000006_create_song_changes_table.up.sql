@@ -0,0 +1,11 @@
+-- Change feed of songs. Writers serialize on an advisory lock, so IDs are
+-- committed in order and consumers can resume from the last ID they saw.
+CREATE TABLE song_changes (
+                              id BIGSERIAL PRIMARY KEY,
+                              occurred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                              change VARCHAR(32) NOT NULL,
+                              song_id UUID NOT NULL,
+                              song JSONB
+);
+
+CREATE INDEX idx_song_changes_occurred_at ON song_changes (occurred_at);
@@ -0,0 +1,29 @@
+CREATE TABLE webhook_subscriptions (
+                                       id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                       url TEXT NOT NULL,
+                                       events TEXT[] NOT NULL,
+                                       secret VARCHAR(255) NOT NULL,
+                                       created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                       deleted_at TIMESTAMP
+);
+
+-- Deliveries of song events to subscriptions, kept as the delivery log once
+-- sent. They are queued from the events relayed by the outbox.
+CREATE TABLE webhook_deliveries (
+                                    id BIGSERIAL PRIMARY KEY,
+                                    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions (id),
+                                    event_id UUID NOT NULL,
+                                    event_type VARCHAR(64) NOT NULL,
+                                    payload JSONB NOT NULL,
+                                    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+                                    attempts INT NOT NULL DEFAULT 0,
+                                    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                    last_attempt_at TIMESTAMP,
+                                    last_status_code INT,
+                                    last_error TEXT,
+                                    delivered_at TIMESTAMP,
+                                    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_subscription ON webhook_deliveries (subscription_id, id);
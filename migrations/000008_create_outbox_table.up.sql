@@ -0,0 +1,18 @@
+-- Outbox of domain events. Rows are written in the transaction of the change
+-- and relayed to the event sinks afterwards, at least once.
+CREATE TABLE outbox (
+                        id BIGSERIAL PRIMARY KEY,
+                        event_id UUID NOT NULL UNIQUE,
+                        event_type VARCHAR(64) NOT NULL,
+                        aggregate_type VARCHAR(64) NOT NULL,
+                        aggregate_id VARCHAR(255) NOT NULL,
+                        payload JSONB NOT NULL,
+                        occurred_at TIMESTAMP NOT NULL,
+                        attempts INTEGER NOT NULL DEFAULT 0,
+                        next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                        last_error TEXT,
+                        published_at TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_pending ON outbox (next_attempt_at, id) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_published_at ON outbox (published_at) WHERE published_at IS NOT NULL;
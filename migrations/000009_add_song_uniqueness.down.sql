@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_songs_group_title_unique;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS unique_enforced;
@@ -0,0 +1,9 @@
+-- Songs written while POSTGRES_UNIQUE_SONGS is enabled are marked unique, the
+-- index only covers them so the setting can differ between deployments.
+-- The index was created at startup before, it is recreated with the flag.
+DROP INDEX IF EXISTS idx_songs_group_title_unique;
+
+ALTER TABLE songs ADD COLUMN unique_enforced BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE UNIQUE INDEX idx_songs_group_title_unique ON songs (lower(btrim("group")), lower(btrim(title)))
+    WHERE deleted_at IS NULL AND unique_enforced;
@@ -0,0 +1,3 @@
+-- Deliveries are queued by the outbox relay, which publishes at least once.
+-- One delivery per subscription and event keeps relayed duplicates out.
+CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries (subscription_id, event_id);
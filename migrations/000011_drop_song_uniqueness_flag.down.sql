@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_songs_group_title_unique;
+
+ALTER TABLE songs ADD COLUMN unique_enforced BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE UNIQUE INDEX idx_songs_group_title_unique ON songs (lower(btrim("group")), lower(btrim(title)))
+    WHERE deleted_at IS NULL AND unique_enforced;
@@ -0,0 +1,5 @@
+-- Uniqueness is enforced by the index alone, it is built and dropped with the
+-- uniqueness command instead of following a per-row flag written by replicas.
+DROP INDEX IF EXISTS idx_songs_group_title_unique;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS unique_enforced;
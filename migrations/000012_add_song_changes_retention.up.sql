@@ -0,0 +1,8 @@
+-- The newest change removed by retention. Consumers resuming from an older ID
+-- have missed changes, IDs alone can not tell since rolled back writes leave gaps.
+CREATE TABLE song_changes_retention (
+                                        singleton BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (singleton),
+                                        purged_through BIGINT NOT NULL
+);
+
+INSERT INTO song_changes_retention (purged_through) VALUES (0);
@@ -0,0 +1,841 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: song/v1/song.proto
+
+package songv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Song struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Group         string                 `protobuf:"bytes,3,opt,name=group,proto3" json:"group,omitempty"`
+	Text          *string                `protobuf:"bytes,4,opt,name=text,proto3,oneof" json:"text,omitempty"`
+	Link          *string                `protobuf:"bytes,5,opt,name=link,proto3,oneof" json:"link,omitempty"`
+	ReleaseDate   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=release_date,json=releaseDate,proto3" json:"release_date,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Song) Reset() {
+	*x = Song{}
+	mi := &file_song_v1_song_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Song) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Song) ProtoMessage() {}
+
+func (x *Song) ProtoReflect() protoreflect.Message {
+	mi := &file_song_v1_song_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Song.ProtoReflect.Descriptor instead.
+func (*Song) Descriptor() ([]byte, []int) {
+	return file_song_v1_song_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Song) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Song) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Song) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *Song) GetText() string {
+	if x != nil && x.Text != nil {
+		return *x.Text
+	}
+	return ""
+}
+
+func (x *Song) GetLink() string {
+	if x != nil && x.Link != nil {
+		return *x.Link
+	}
+	return ""
+}
+
+func (x *Song) GetReleaseDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReleaseDate
+	}
+	return nil
+}
+
+func (x *Song) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Song) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// SongFilter matches songs whose fields contain the given values, ignoring case.
+type SongFilter struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Title           *string                `protobuf:"bytes,1,opt,name=title,proto3,oneof" json:"title,omitempty"`
+	Group           *string                `protobuf:"bytes,2,opt,name=group,proto3,oneof" json:"group,omitempty"`
+	Text            *string                `protobuf:"bytes,3,opt,name=text,proto3,oneof" json:"text,omitempty"`
+	Link            *string                `protobuf:"bytes,4,opt,name=link,proto3,oneof" json:"link,omitempty"`
+	ReleaseDateFrom *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=release_date_from,json=releaseDateFrom,proto3" json:"release_date_from,omitempty"`
+	ReleaseDateTo   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=release_date_to,json=releaseDateTo,proto3" json:"release_date_to,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SongFilter) Reset() {
+	*x = SongFilter{}
+	mi := &file_song_v1_song_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SongFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SongFilter) ProtoMessage() {}
+
+func (x *SongFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_song_v1_song_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SongFilter.ProtoReflect.Descriptor instead.
+func (*SongFilter) Descriptor() ([]byte, []int) {
+	return file_song_v1_song_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SongFilter) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *SongFilter) GetGroup() string {
+	if x != nil && x.Group != nil {
+		return *x.Group
+	}
+	return ""
+}
+
+func (x *SongFilter) GetText() string {
+	if x != nil && x.Text != nil {
+		return *x.Text
+	}
+	return ""
+}
+
+func (x *SongFilter) GetLink() string {
+	if x != nil && x.Link != nil {
+		return *x.Link
+	}
+	return ""
+}
+
+func (x *SongFilter) GetReleaseDateFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReleaseDateFrom
+	}
+	return nil
+}
+
+func (x *SongFilter) GetReleaseDateTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReleaseDateTo
+	}
+	return nil
+}
+
+type ListSongsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *SongFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	Page   int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	// Defaults to 10.
+	PageSize      int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSongsRequest) Reset() {
+	*x = ListSongsRequest{}
+	mi := &file_song_v1_song_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSongsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSongsRequest) ProtoMessage() {}
+
+func (x *ListSongsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_song_v1_song_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSongsRequest.ProtoReflect.Descriptor instead.
+func (*ListSongsRequest) Descriptor() ([]byte, []int) {
+	return file_song_v1_song_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListSongsRequest) GetFilter() *SongFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListSongsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListSongsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListSongsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Songs         []*Song                `protobuf:"bytes,1,rep,name=songs,proto3" json:"songs,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSongsResponse) Reset() {
+	*x = ListSongsResponse{}
+	mi := &file_song_v1_song_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSongsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSongsResponse) ProtoMessage() {}
+
+func (x *ListSongsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_song_v1_song_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSongsResponse.ProtoReflect.Descriptor instead.
+func (*ListSongsResponse) Descriptor() ([]byte, []int) {
+	return file_song_v1_song_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListSongsResponse) GetSongs() []*Song {
+	if x != nil {
+		return x.Songs
+	}
+	return nil
+}
+
+func (x *ListSongsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListSongsResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type GetSongRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSongRequest) Reset() {
+	*x = GetSongRequest{}
+	mi := &file_song_v1_song_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSongRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSongRequest) ProtoMessage() {}
+
+func (x *GetSongRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_song_v1_song_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSongRequest.ProtoReflect.Descriptor instead.
+func (*GetSongRequest) Descriptor() ([]byte, []int) {
+	return file_song_v1_song_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetSongRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CreateSongRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Group         string                 `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
+	Text          *string                `protobuf:"bytes,3,opt,name=text,proto3,oneof" json:"text,omitempty"`
+	Link          *string                `protobuf:"bytes,4,opt,name=link,proto3,oneof" json:"link,omitempty"`
+	ReleaseDate   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=release_date,json=releaseDate,proto3" json:"release_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSongRequest) Reset() {
+	*x = CreateSongRequest{}
+	mi := &file_song_v1_song_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSongRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSongRequest) ProtoMessage() {}
+
+func (x *CreateSongRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_song_v1_song_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSongRequest.ProtoReflect.Descriptor instead.
+func (*CreateSongRequest) Descriptor() ([]byte, []int) {
+	return file_song_v1_song_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateSongRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateSongRequest) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *CreateSongRequest) GetText() string {
+	if x != nil && x.Text != nil {
+		return *x.Text
+	}
+	return ""
+}
+
+func (x *CreateSongRequest) GetLink() string {
+	if x != nil && x.Link != nil {
+		return *x.Link
+	}
+	return ""
+}
+
+func (x *CreateSongRequest) GetReleaseDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReleaseDate
+	}
+	return nil
+}
+
+// UpdateSongRequest changes only the fields that are set.
+type UpdateSongRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         *string                `protobuf:"bytes,2,opt,name=title,proto3,oneof" json:"title,omitempty"`
+	Group         *string                `protobuf:"bytes,3,opt,name=group,proto3,oneof" json:"group,omitempty"`
+	Text          *string                `protobuf:"bytes,4,opt,name=text,proto3,oneof" json:"text,omitempty"`
+	Link          *string                `protobuf:"bytes,5,opt,name=link,proto3,oneof" json:"link,omitempty"`
+	ReleaseDate   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=release_date,json=releaseDate,proto3" json:"release_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSongRequest) Reset() {
+	*x = UpdateSongRequest{}
+	mi := &file_song_v1_song_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSongRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSongRequest) ProtoMessage() {}
+
+func (x *UpdateSongRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_song_v1_song_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSongRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSongRequest) Descriptor() ([]byte, []int) {
+	return file_song_v1_song_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateSongRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateSongRequest) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *UpdateSongRequest) GetGroup() string {
+	if x != nil && x.Group != nil {
+		return *x.Group
+	}
+	return ""
+}
+
+func (x *UpdateSongRequest) GetText() string {
+	if x != nil && x.Text != nil {
+		return *x.Text
+	}
+	return ""
+}
+
+func (x *UpdateSongRequest) GetLink() string {
+	if x != nil && x.Link != nil {
+		return *x.Link
+	}
+	return ""
+}
+
+func (x *UpdateSongRequest) GetReleaseDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReleaseDate
+	}
+	return nil
+}
+
+type DeleteSongRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Removes the song for good instead of moving it to the trash.
+	Permanent     bool `protobuf:"varint,2,opt,name=permanent,proto3" json:"permanent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSongRequest) Reset() {
+	*x = DeleteSongRequest{}
+	mi := &file_song_v1_song_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSongRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSongRequest) ProtoMessage() {}
+
+func (x *DeleteSongRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_song_v1_song_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSongRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSongRequest) Descriptor() ([]byte, []int) {
+	return file_song_v1_song_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteSongRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteSongRequest) GetPermanent() bool {
+	if x != nil {
+		return x.Permanent
+	}
+	return false
+}
+
+type RestoreSongRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreSongRequest) Reset() {
+	*x = RestoreSongRequest{}
+	mi := &file_song_v1_song_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreSongRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreSongRequest) ProtoMessage() {}
+
+func (x *RestoreSongRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_song_v1_song_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreSongRequest.ProtoReflect.Descriptor instead.
+func (*RestoreSongRequest) Descriptor() ([]byte, []int) {
+	return file_song_v1_song_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RestoreSongRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ExportSongsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *SongFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportSongsRequest) Reset() {
+	*x = ExportSongsRequest{}
+	mi := &file_song_v1_song_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportSongsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportSongsRequest) ProtoMessage() {}
+
+func (x *ExportSongsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_song_v1_song_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportSongsRequest.ProtoReflect.Descriptor instead.
+func (*ExportSongsRequest) Descriptor() ([]byte, []int) {
+	return file_song_v1_song_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ExportSongsRequest) GetFilter() *SongFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+var File_song_v1_song_proto protoreflect.FileDescriptor
+
+const file_song_v1_song_proto_rawDesc = "" +
+	"\n" +
+	"\x12song/v1/song.proto\x12\asong.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xbb\x02\n" +
+	"\x04Song\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x14\n" +
+	"\x05group\x18\x03 \x01(\tR\x05group\x12\x17\n" +
+	"\x04text\x18\x04 \x01(\tH\x00R\x04text\x88\x01\x01\x12\x17\n" +
+	"\x04link\x18\x05 \x01(\tH\x01R\x04link\x88\x01\x01\x12=\n" +
+	"\frelease_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vreleaseDate\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\a\n" +
+	"\x05_textB\a\n" +
+	"\x05_link\"\xa6\x02\n" +
+	"\n" +
+	"SongFilter\x12\x19\n" +
+	"\x05title\x18\x01 \x01(\tH\x00R\x05title\x88\x01\x01\x12\x19\n" +
+	"\x05group\x18\x02 \x01(\tH\x01R\x05group\x88\x01\x01\x12\x17\n" +
+	"\x04text\x18\x03 \x01(\tH\x02R\x04text\x88\x01\x01\x12\x17\n" +
+	"\x04link\x18\x04 \x01(\tH\x03R\x04link\x88\x01\x01\x12F\n" +
+	"\x11release_date_from\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x0freleaseDateFrom\x12B\n" +
+	"\x0frelease_date_to\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\rreleaseDateToB\b\n" +
+	"\x06_titleB\b\n" +
+	"\x06_groupB\a\n" +
+	"\x05_textB\a\n" +
+	"\x05_link\"p\n" +
+	"\x10ListSongsRequest\x12+\n" +
+	"\x06filter\x18\x01 \x01(\v2\x13.song.v1.SongFilterR\x06filter\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"i\n" +
+	"\x11ListSongsResponse\x12#\n" +
+	"\x05songs\x18\x01 \x03(\v2\r.song.v1.SongR\x05songs\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\" \n" +
+	"\x0eGetSongRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xc2\x01\n" +
+	"\x11CreateSongRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x14\n" +
+	"\x05group\x18\x02 \x01(\tR\x05group\x12\x17\n" +
+	"\x04text\x18\x03 \x01(\tH\x00R\x04text\x88\x01\x01\x12\x17\n" +
+	"\x04link\x18\x04 \x01(\tH\x01R\x04link\x88\x01\x01\x12=\n" +
+	"\frelease_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vreleaseDateB\a\n" +
+	"\x05_textB\a\n" +
+	"\x05_link\"\xf0\x01\n" +
+	"\x11UpdateSongRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\x05title\x18\x02 \x01(\tH\x00R\x05title\x88\x01\x01\x12\x19\n" +
+	"\x05group\x18\x03 \x01(\tH\x01R\x05group\x88\x01\x01\x12\x17\n" +
+	"\x04text\x18\x04 \x01(\tH\x02R\x04text\x88\x01\x01\x12\x17\n" +
+	"\x04link\x18\x05 \x01(\tH\x03R\x04link\x88\x01\x01\x12=\n" +
+	"\frelease_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vreleaseDateB\b\n" +
+	"\x06_titleB\b\n" +
+	"\x06_groupB\a\n" +
+	"\x05_textB\a\n" +
+	"\x05_link\"A\n" +
+	"\x11DeleteSongRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\tpermanent\x18\x02 \x01(\bR\tpermanent\"$\n" +
+	"\x12RestoreSongRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"A\n" +
+	"\x12ExportSongsRequest\x12+\n" +
+	"\x06filter\x18\x01 \x01(\v2\x13.song.v1.SongFilterR\x06filter2\xb9\x03\n" +
+	"\vSongService\x12B\n" +
+	"\tListSongs\x12\x19.song.v1.ListSongsRequest\x1a\x1a.song.v1.ListSongsResponse\x121\n" +
+	"\aGetSong\x12\x17.song.v1.GetSongRequest\x1a\r.song.v1.Song\x127\n" +
+	"\n" +
+	"CreateSong\x12\x1a.song.v1.CreateSongRequest\x1a\r.song.v1.Song\x127\n" +
+	"\n" +
+	"UpdateSong\x12\x1a.song.v1.UpdateSongRequest\x1a\r.song.v1.Song\x12@\n" +
+	"\n" +
+	"DeleteSong\x12\x1a.song.v1.DeleteSongRequest\x1a\x16.google.protobuf.Empty\x12B\n" +
+	"\vRestoreSong\x12\x1b.song.v1.RestoreSongRequest\x1a\x16.google.protobuf.Empty\x12;\n" +
+	"\vExportSongs\x12\x1b.song.v1.ExportSongsRequest\x1a\r.song.v1.Song0\x01B<Z:github.com/orungrau/em_song_library/pkg/api/song/v1;songv1b\x06proto3"
+
+var (
+	file_song_v1_song_proto_rawDescOnce sync.Once
+	file_song_v1_song_proto_rawDescData []byte
+)
+
+func file_song_v1_song_proto_rawDescGZIP() []byte {
+	file_song_v1_song_proto_rawDescOnce.Do(func() {
+		file_song_v1_song_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_song_v1_song_proto_rawDesc), len(file_song_v1_song_proto_rawDesc)))
+	})
+	return file_song_v1_song_proto_rawDescData
+}
+
+var file_song_v1_song_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_song_v1_song_proto_goTypes = []any{
+	(*Song)(nil),                  // 0: song.v1.Song
+	(*SongFilter)(nil),            // 1: song.v1.SongFilter
+	(*ListSongsRequest)(nil),      // 2: song.v1.ListSongsRequest
+	(*ListSongsResponse)(nil),     // 3: song.v1.ListSongsResponse
+	(*GetSongRequest)(nil),        // 4: song.v1.GetSongRequest
+	(*CreateSongRequest)(nil),     // 5: song.v1.CreateSongRequest
+	(*UpdateSongRequest)(nil),     // 6: song.v1.UpdateSongRequest
+	(*DeleteSongRequest)(nil),     // 7: song.v1.DeleteSongRequest
+	(*RestoreSongRequest)(nil),    // 8: song.v1.RestoreSongRequest
+	(*ExportSongsRequest)(nil),    // 9: song.v1.ExportSongsRequest
+	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 11: google.protobuf.Empty
+}
+var file_song_v1_song_proto_depIdxs = []int32{
+	10, // 0: song.v1.Song.release_date:type_name -> google.protobuf.Timestamp
+	10, // 1: song.v1.Song.created_at:type_name -> google.protobuf.Timestamp
+	10, // 2: song.v1.Song.updated_at:type_name -> google.protobuf.Timestamp
+	10, // 3: song.v1.SongFilter.release_date_from:type_name -> google.protobuf.Timestamp
+	10, // 4: song.v1.SongFilter.release_date_to:type_name -> google.protobuf.Timestamp
+	1,  // 5: song.v1.ListSongsRequest.filter:type_name -> song.v1.SongFilter
+	0,  // 6: song.v1.ListSongsResponse.songs:type_name -> song.v1.Song
+	10, // 7: song.v1.CreateSongRequest.release_date:type_name -> google.protobuf.Timestamp
+	10, // 8: song.v1.UpdateSongRequest.release_date:type_name -> google.protobuf.Timestamp
+	1,  // 9: song.v1.ExportSongsRequest.filter:type_name -> song.v1.SongFilter
+	2,  // 10: song.v1.SongService.ListSongs:input_type -> song.v1.ListSongsRequest
+	4,  // 11: song.v1.SongService.GetSong:input_type -> song.v1.GetSongRequest
+	5,  // 12: song.v1.SongService.CreateSong:input_type -> song.v1.CreateSongRequest
+	6,  // 13: song.v1.SongService.UpdateSong:input_type -> song.v1.UpdateSongRequest
+	7,  // 14: song.v1.SongService.DeleteSong:input_type -> song.v1.DeleteSongRequest
+	8,  // 15: song.v1.SongService.RestoreSong:input_type -> song.v1.RestoreSongRequest
+	9,  // 16: song.v1.SongService.ExportSongs:input_type -> song.v1.ExportSongsRequest
+	3,  // 17: song.v1.SongService.ListSongs:output_type -> song.v1.ListSongsResponse
+	0,  // 18: song.v1.SongService.GetSong:output_type -> song.v1.Song
+	0,  // 19: song.v1.SongService.CreateSong:output_type -> song.v1.Song
+	0,  // 20: song.v1.SongService.UpdateSong:output_type -> song.v1.Song
+	11, // 21: song.v1.SongService.DeleteSong:output_type -> google.protobuf.Empty
+	11, // 22: song.v1.SongService.RestoreSong:output_type -> google.protobuf.Empty
+	0,  // 23: song.v1.SongService.ExportSongs:output_type -> song.v1.Song
+	17, // [17:24] is the sub-list for method output_type
+	10, // [10:17] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_song_v1_song_proto_init() }
+func file_song_v1_song_proto_init() {
+	if File_song_v1_song_proto != nil {
+		return
+	}
+	file_song_v1_song_proto_msgTypes[0].OneofWrappers = []any{}
+	file_song_v1_song_proto_msgTypes[1].OneofWrappers = []any{}
+	file_song_v1_song_proto_msgTypes[5].OneofWrappers = []any{}
+	file_song_v1_song_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_song_v1_song_proto_rawDesc), len(file_song_v1_song_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_song_v1_song_proto_goTypes,
+		DependencyIndexes: file_song_v1_song_proto_depIdxs,
+		MessageInfos:      file_song_v1_song_proto_msgTypes,
+	}.Build()
+	File_song_v1_song_proto = out.File
+	file_song_v1_song_proto_goTypes = nil
+	file_song_v1_song_proto_depIdxs = nil
+}
@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache stores values by key for a while. Local keeps them in process,
+// adapters of external caches such as Redis implement it to share entries
+// between replicas and report their failures as misses.
+type Cache[V any] interface {
+	Get(ctx context.Context, key string) (V, bool)
+	Set(ctx context.Context, key string, value V)
+	Delete(ctx context.Context, keys ...string)
+}
+
+// Local is a Cache backed by an LRU.
+type Local[V any] struct {
+	lru *LRU[string, V]
+}
+
+func NewLocal[V any](size int, ttl time.Duration) *Local[V] {
+	return &Local[V]{lru: NewLRU[string, V](size, ttl)}
+}
+
+func (c *Local[V]) Get(_ context.Context, key string) (V, bool) {
+	return c.lru.Get(key)
+}
+
+func (c *Local[V]) Set(_ context.Context, key string, value V) {
+	c.lru.Set(key, value)
+}
+
+func (c *Local[V]) Delete(_ context.Context, keys ...string) {
+	for _, key := range keys {
+		c.lru.Delete(key)
+	}
+}
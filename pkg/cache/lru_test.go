@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	lru := NewLRU[string, int](2, 0)
+
+	lru.Set("a", 1)
+	lru.Set("b", 2)
+	// Reading a makes b the least recently used
+	if _, ok := lru.Get("a"); !ok {
+		t.Fatal("a missing before eviction")
+	}
+	lru.Set("c", 3)
+
+	if _, ok := lru.Get("b"); ok {
+		t.Error("b kept, want it evicted")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if got, ok := lru.Get(key); !ok || got != want {
+			t.Errorf("Get(%q) = %d, %v, want %d, true", key, got, ok, want)
+		}
+	}
+	if lru.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", lru.Len())
+	}
+}
+
+func TestLRUSetReplacesValue(t *testing.T) {
+	lru := NewLRU[string, int](2, 0)
+
+	lru.Set("a", 1)
+	lru.Set("b", 2)
+	lru.Set("a", 10)
+	lru.Set("c", 3)
+
+	if got, ok := lru.Get("a"); !ok || got != 10 {
+		t.Errorf("Get(a) = %d, %v, want 10, true", got, ok)
+	}
+	if _, ok := lru.Get("b"); ok {
+		t.Error("b kept, want it evicted after a was replaced")
+	}
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		want bool
+	}{
+		{name: "expired", ttl: 10 * time.Millisecond, want: false},
+		{name: "without expiry", ttl: 0, want: true},
+		{name: "fresh", ttl: time.Hour, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lru := NewLRU[string, int](2, tt.ttl)
+			lru.Set("a", 1)
+
+			time.Sleep(20 * time.Millisecond)
+
+			if _, ok := lru.Get("a"); ok != tt.want {
+				t.Errorf("Get(a) found = %v, want %v", ok, tt.want)
+			}
+			if !tt.want && lru.Len() != 0 {
+				t.Errorf("Len() = %d, want the expired entry removed", lru.Len())
+			}
+		})
+	}
+}
+
+func TestLRUDeleteAndPurge(t *testing.T) {
+	lru := NewLRU[string, int](3, 0)
+	lru.Set("a", 1)
+	lru.Set("b", 2)
+	lru.Set("c", 3)
+
+	lru.Delete("a")
+	if _, ok := lru.Get("a"); ok {
+		t.Error("a kept after Delete")
+	}
+
+	lru.Purge()
+	if lru.Len() != 0 {
+		t.Errorf("Len() after Purge = %d, want 0", lru.Len())
+	}
+	lru.Set("d", 4)
+	if got, ok := lru.Get("d"); !ok || got != 4 {
+		t.Errorf("Get(d) after Purge = %d, %v, want 4, true", got, ok)
+	}
+}
+
+func TestLRUWithoutSizeCachesNothing(t *testing.T) {
+	lru := NewLRU[string, int](0, 0)
+	lru.Set("a", 1)
+
+	if _, ok := lru.Get("a"); ok {
+		t.Error("zero sized cache kept an entry")
+	}
+}
+
+func TestLocalDeletesEveryKey(t *testing.T) {
+	ctx := context.Background()
+	local := NewLocal[int](3, time.Minute)
+	local.Set(ctx, "a", 1)
+	local.Set(ctx, "b", 2)
+	local.Set(ctx, "c", 3)
+
+	local.Delete(ctx, "a", "b")
+
+	for key, want := range map[string]bool{"a": false, "b": false, "c": true} {
+		if _, ok := local.Get(ctx, key); ok != want {
+			t.Errorf("Get(%q) found = %v, want %v", key, ok, want)
+		}
+	}
+}
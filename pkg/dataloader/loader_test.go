@@ -0,0 +1,136 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingFetch doubles keys and records the batches it was called with.
+type recordingFetch struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (f *recordingFetch) fetch(_ context.Context, keys []int) (map[int]int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.batches = append(f.batches, slices.Sorted(slices.Values(keys)))
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	values := make(map[int]int, len(keys))
+	for _, key := range keys {
+		values[key] = key * 2
+	}
+	return values, nil
+}
+
+func (f *recordingFetch) calls() [][]int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.batches)
+}
+
+// loadAll loads keys concurrently and returns the values in key order.
+func loadAll(t *testing.T, loader *Loader[int, int], keys ...int) ([]int, []error) {
+	t.Helper()
+
+	values := make([]int, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = loader.Load(context.Background(), key)
+		}()
+	}
+	wg.Wait()
+
+	return values, errs
+}
+
+func TestLoaderBatchesConcurrentLoads(t *testing.T) {
+	fetch := &recordingFetch{}
+	loader := New(fetch.fetch, 50*time.Millisecond, 0)
+
+	values, errs := loadAll(t, loader, 1, 2, 3, 2)
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Load() #%d error = %v", i, err)
+		}
+	}
+	if want := []int{2, 4, 6, 4}; !slices.Equal(values, want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+	if calls := fetch.calls(); len(calls) != 1 || !slices.Equal(calls[0], []int{1, 2, 3}) {
+		t.Errorf("batches = %v, want [[1 2 3]]", calls)
+	}
+
+	// Loaded keys are memoized, only the new one is fetched
+	if _, errs := loadAll(t, loader, 1, 4); errs[0] != nil || errs[1] != nil {
+		t.Fatalf("Load() errors = %v", errs)
+	}
+	if calls := fetch.calls(); len(calls) != 2 || !slices.Equal(calls[1], []int{4}) {
+		t.Errorf("batches = %v, want [[1 2 3] [4]]", calls)
+	}
+}
+
+func TestLoaderSplitsFullBatches(t *testing.T) {
+	fetch := &recordingFetch{}
+	loader := New(fetch.fetch, time.Hour, 2)
+
+	// A full batch runs at once, the wait window is never reached
+	if _, errs := loadAll(t, loader, 1, 2); errs[0] != nil || errs[1] != nil {
+		t.Fatalf("Load() errors = %v", errs)
+	}
+	if calls := fetch.calls(); len(calls) != 1 || !slices.Equal(calls[0], []int{1, 2}) {
+		t.Errorf("batches = %v, want [[1 2]]", calls)
+	}
+}
+
+func TestLoaderRetriesFailedKeys(t *testing.T) {
+	errFetch := errors.New("storage unavailable")
+	fetch := &recordingFetch{err: errFetch}
+	loader := New(fetch.fetch, time.Millisecond, 0)
+
+	if _, err := loader.Load(context.Background(), 1); !errors.Is(err, errFetch) {
+		t.Fatalf("Load() error = %v, want %v", err, errFetch)
+	}
+
+	fetch.mu.Lock()
+	fetch.err = nil
+	fetch.mu.Unlock()
+
+	value, err := loader.Load(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Load() after recovery error = %v", err)
+	}
+	if value != 2 {
+		t.Errorf("value = %d, want 2", value)
+	}
+	if calls := fetch.calls(); len(calls) != 2 {
+		t.Errorf("fetched %d times, want 2", len(calls))
+	}
+}
+
+func TestLoaderStopsWaitingWhenContextEnds(t *testing.T) {
+	loader := New(func(ctx context.Context, keys []int) (map[int]int, error) {
+		return nil, nil
+	}, time.Hour, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := loader.Load(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Load() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,77 @@
+package fuzzy
+
+import (
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+// Normalize lowercases the string, strips diacritics and collapses whitespace.
+func Normalize(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		folded = s
+	}
+
+	return strings.Join(strings.Fields(strings.ToLower(folded)), " ")
+}
+
+func Levenshtein(a, b []rune) int {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+// Similarity scores query against target in the range [0, 1]. Besides the
+// whole strings, every run of target words as long as the query is compared,
+// so a query matching only part of the target still scores high.
+func Similarity(query, target string) float64 {
+	query, target = Normalize(query), Normalize(target)
+	if query == "" || target == "" {
+		return 0
+	}
+
+	best := ratio(query, target)
+
+	queryWords := strings.Fields(query)
+	targetWords := strings.Fields(target)
+	for i := 0; i+len(queryWords) <= len(targetWords); i++ {
+		window := strings.Join(targetWords[i:i+len(queryWords)], " ")
+		best = max(best, ratio(query, window))
+	}
+
+	return best
+}
+
+func ratio(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+
+	return 1 - float64(Levenshtein(ra, rb))/float64(longest)
+}
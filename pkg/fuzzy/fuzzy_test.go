@@ -0,0 +1,75 @@
+package fuzzy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "empty", in: "", want: ""},
+		{name: "lowercase", in: "Muse", want: "muse"},
+		{name: "diacritics", in: "Beyoncé Motörhead", want: "beyonce motorhead"},
+		{name: "whitespace", in: "  Supermassive \t Black\nHole ", want: "supermassive black hole"},
+		{name: "cyrillic", in: "Кино  Группа", want: "кино группа"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "muse", b: "", want: 4},
+		{a: "", b: "muse", want: 4},
+		{a: "muse", b: "muse", want: 0},
+		{a: "muse", b: "mose", want: 1},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "sitting", b: "kitten", want: 3},
+		{a: "кино", b: "кина", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := Levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.want {
+				t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name          string
+		query, target string
+		want          float64
+	}{
+		{name: "empty query", query: "", target: "Muse", want: 0},
+		{name: "empty target", query: "Muse", target: "", want: 0},
+		{name: "equal after normalization", query: "beyonce", target: "Beyoncé", want: 1},
+		{name: "typo", query: "hysteira", target: "Hysteria", want: 0.75},
+		{name: "part of target", query: "black hole", target: "Supermassive Black Hole", want: 1},
+		{name: "unrelated", query: "abc", target: "xyz", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Similarity(tt.query, tt.target); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Similarity(%q, %q) = %v, want %v", tt.query, tt.target, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,20 +1,31 @@
 package logger
 
 import (
+	"context"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 	"time"
 )
 
+type startTimeKey struct{}
+
+// WithStartTime marks when handling of the request began, so that log events
+// written with the context report the elapsed duration.
+func WithStartTime(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, startTimeKey{}, start)
+}
+
 type TracingHook struct{}
 
 func (h TracingHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
 	ctx := e.GetCtx()
 
-	if duration, ok := ctx.Value("duration").(time.Time); ok {
-		e.Dur("duration", time.Since(duration))
+	if start, ok := ctx.Value(startTimeKey{}).(time.Time); ok {
+		e.Dur("duration", time.Since(start))
 	}
 
-	if spanId, ok := ctx.Value("span-id").(string); ok && spanId != "" {
-		e.Str("span-id", spanId)
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		e.Str("trace_id", spanContext.TraceID().String())
+		e.Str("span_id", spanContext.SpanID().String())
 	}
 }
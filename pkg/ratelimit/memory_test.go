@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newTestStore returns a store with a clock the test moves forward.
+func newTestStore() (*MemoryStore, func(time.Duration)) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.lastSweep = now
+	store.now = func() time.Time { return now }
+
+	return store, func(d time.Duration) { now = now.Add(d) }
+}
+
+func allow(t *testing.T, store *MemoryStore, key string, limit Limit) Result {
+	t.Helper()
+
+	result, err := store.Allow(context.Background(), key, limit)
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+
+	return result
+}
+
+func TestMemoryStoreAllowsBurstThenRejects(t *testing.T) {
+	store, _ := newTestStore()
+	limit := Limit{Requests: 3, Period: time.Minute}
+
+	for i := range limit.Requests {
+		result := allow(t, store, "client", limit)
+		if !result.Allowed {
+			t.Fatalf("request %d rejected within the burst", i+1)
+		}
+		if want := limit.Requests - i - 1; result.Remaining != want {
+			t.Errorf("request %d: remaining = %d, want %d", i+1, result.Remaining, want)
+		}
+	}
+
+	result := allow(t, store, "client", limit)
+	if result.Allowed {
+		t.Fatal("request over the burst allowed")
+	}
+	if result.RetryAfter != 20*time.Second {
+		t.Errorf("retry after = %v, want 20s", result.RetryAfter)
+	}
+	if result.ResetAfter != time.Minute {
+		t.Errorf("reset after = %v, want 1m", result.ResetAfter)
+	}
+
+	if other := allow(t, store, "other", limit); !other.Allowed {
+		t.Error("another key shares the bucket")
+	}
+}
+
+func TestMemoryStoreRefillsTokens(t *testing.T) {
+	limit := Limit{Requests: 3, Period: time.Minute}
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		allowed int
+	}{
+		{name: "not yet", elapsed: 19 * time.Second, allowed: 0},
+		{name: "one token", elapsed: 20 * time.Second, allowed: 1},
+		{name: "two tokens", elapsed: 45 * time.Second, allowed: 2},
+		{name: "capped at the burst", elapsed: time.Hour, allowed: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, advance := newTestStore()
+			for range limit.Requests {
+				allow(t, store, "client", limit)
+			}
+
+			advance(tt.elapsed)
+
+			allowed := 0
+			for allow(t, store, "client", limit).Allowed {
+				allowed++
+			}
+			if allowed != tt.allowed {
+				t.Errorf("allowed = %d, want %d", allowed, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Limit
+		wantErr bool
+	}{
+		{value: "100/1m", want: Limit{Requests: 100, Period: time.Minute}},
+		{value: " 5/10s ", want: Limit{Requests: 5, Period: 10 * time.Second}},
+		{value: "100", wantErr: true},
+		{value: "0/1m", wantErr: true},
+		{value: "10/0s", wantErr: true},
+		{value: "ten/1m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseLimit(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLimit(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLimit(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
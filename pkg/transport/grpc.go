@@ -0,0 +1,119 @@
+package transport
+
+import (
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"net"
+	"sync"
+	"time"
+)
+
+type GRPCServerConfig interface {
+	GetAddress() string
+	GetShutdownTimeout() time.Duration
+	GetTLSCertFile() string
+	GetTLSKeyFile() string
+	// GetTLSClientCAFile enables mTLS, client certificates are verified against it.
+	GetTLSClientCAFile() string
+	GetTLSClientAuthOptional() bool
+	GetTLSReloadInterval() time.Duration
+}
+
+// GRPCServerOptions returns the TLS credentials of the server when a
+// certificate is configured, reloaded on change like the HTTP server's.
+// Without one the server accepts plaintext connections.
+func GRPCServerOptions(log zerolog.Logger, cfg GRPCServerConfig) ([]grpc.ServerOption, error) {
+	if cfg.GetTLSCertFile() == "" && cfg.GetTLSKeyFile() == "" {
+		return nil, nil
+	}
+
+	tlsConfig, err := newServerTLSConfig(
+		log.With().Str("module", "grpc-server").Logger(),
+		cfg.GetTLSCertFile(),
+		cfg.GetTLSKeyFile(),
+		cfg.GetTLSClientCAFile(),
+		cfg.GetTLSClientAuthOptional(),
+		cfg.GetTLSReloadInterval(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))}, nil
+}
+
+type GRPCServer struct {
+	log     zerolog.Logger
+	server  *grpc.Server
+	health  *health.Server
+	address string
+	tls     bool
+	// shutdownTimeout bounds how long calls in flight may take to finish.
+	shutdownTimeout time.Duration
+	wg              sync.WaitGroup
+}
+
+// NewGRPCServer serves server on the configured address and registers the
+// standard health service on it.
+func NewGRPCServer(log zerolog.Logger, server *grpc.Server, cfg GRPCServerConfig) *GRPCServer {
+	healthServer := health.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+
+	return &GRPCServer{
+		server:          server,
+		health:          healthServer,
+		address:         cfg.GetAddress(),
+		tls:             cfg.GetTLSCertFile() != "",
+		shutdownTimeout: cfg.GetShutdownTimeout(),
+		log:             log.With().Str("module", "grpc-server").Logger(),
+	}
+}
+
+func (s *GRPCServer) MustStart() {
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		s.log.Fatal().Err(err).Msg("gRPC server start failed")
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.log.Info().Str("address", s.address).Bool("tls", s.tls).Msg("Starting gRPC server")
+
+		if err := s.server.Serve(listener); err != nil {
+			s.log.Fatal().Err(err).Msg("gRPC server failed")
+		}
+
+		s.log.Info().Msg("gRPC server stopped")
+	}()
+}
+
+// SetShuttingDown reports every service as not serving so clients move away
+// before the server stops.
+func (s *GRPCServer) SetShuttingDown() {
+	s.health.Shutdown()
+}
+
+func (s *GRPCServer) Stop() {
+	s.log.Info().Dur("timeout", s.shutdownTimeout).Msg("Stopping gRPC server")
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(s.shutdownTimeout):
+		s.log.Warn().Msg("gRPC server did not drain in time, closing remaining calls")
+		s.server.Stop()
+	}
+
+	s.wg.Wait()
+	s.log.Info().Msg("gRPC server shutdown complete")
+}
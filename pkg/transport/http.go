@@ -3,7 +3,10 @@ package transport
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/rs/zerolog"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"net/http"
 	"sync"
 	"time"
@@ -11,31 +14,92 @@ import (
 
 type HTTPServerConfig interface {
 	GetAddress() string
+	GetReadTimeout() time.Duration
+	GetReadHeaderTimeout() time.Duration
+	GetWriteTimeout() time.Duration
+	GetIdleTimeout() time.Duration
+	GetMaxHeaderBytes() int
+	GetShutdownTimeout() time.Duration
+	// GetH2C enables HTTP/2 without TLS, for proxies speaking it to the backend.
+	GetH2C() bool
+	GetTLSCertFile() string
+	GetTLSKeyFile() string
+	// GetTLSClientCAFile enables mTLS, client certificates are verified against it.
+	GetTLSClientCAFile() string
+	GetTLSClientAuthOptional() bool
+	GetTLSReloadInterval() time.Duration
 }
 
 type HTTPServer struct {
-	log    zerolog.Logger
-	server *http.Server
-	wg     sync.WaitGroup
+	log             zerolog.Logger
+	server          *http.Server
+	tls             bool
+	shutdownTimeout time.Duration
+	wg              sync.WaitGroup
 }
 
-func NewHTTPServer(log zerolog.Logger, router http.Handler, cfg HTTPServerConfig) *HTTPServer {
-	return &HTTPServer{
-		server: &http.Server{
-			Addr:    cfg.GetAddress(),
-			Handler: router,
-		},
-		log: log.With().Str("module", "http-server").Logger(),
+// NewHTTPServer serves HTTPS when a certificate is configured, and HTTP/2
+// over TLS or, with h2c, over plain connections.
+func NewHTTPServer(log zerolog.Logger, router http.Handler, cfg HTTPServerConfig) (*HTTPServer, error) {
+	log = log.With().Str("module", "http-server").Logger()
+
+	server := &http.Server{
+		Addr:              cfg.GetAddress(),
+		Handler:           router,
+		ReadTimeout:       cfg.GetReadTimeout(),
+		ReadHeaderTimeout: cfg.GetReadHeaderTimeout(),
+		WriteTimeout:      cfg.GetWriteTimeout(),
+		IdleTimeout:       cfg.GetIdleTimeout(),
+		MaxHeaderBytes:    cfg.GetMaxHeaderBytes(),
 	}
+
+	useTLS := cfg.GetTLSCertFile() != "" || cfg.GetTLSKeyFile() != ""
+	switch {
+	case useTLS && cfg.GetH2C():
+		// TLS already negotiates HTTP/2, h2c would be silently ignored
+		return nil, errors.New("h2c can not be combined with TLS")
+	case useTLS:
+		tlsConfig, err := newServerTLSConfig(
+			log,
+			cfg.GetTLSCertFile(),
+			cfg.GetTLSKeyFile(),
+			cfg.GetTLSClientCAFile(),
+			cfg.GetTLSClientAuthOptional(),
+			cfg.GetTLSReloadInterval(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		server.TLSConfig = tlsConfig
+
+		if err := http2.ConfigureServer(server, &http2.Server{IdleTimeout: cfg.GetIdleTimeout()}); err != nil {
+			return nil, fmt.Errorf("configure HTTP/2: %w", err)
+		}
+	case cfg.GetH2C():
+		server.Handler = h2c.NewHandler(router, &http2.Server{IdleTimeout: cfg.GetIdleTimeout()})
+	}
+
+	return &HTTPServer{
+		log:             log,
+		server:          server,
+		tls:             useTLS,
+		shutdownTimeout: cfg.GetShutdownTimeout(),
+	}, nil
 }
 
 func (s *HTTPServer) MustStart() {
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
-		s.log.Info().Str("address", s.server.Addr).Msg("Starting HTTP server")
+		s.log.Info().Str("address", s.server.Addr).Bool("tls", s.tls).Msg("Starting HTTP server")
 
-		err := s.server.ListenAndServe()
+		var err error
+		if s.tls {
+			// The certificate comes from the TLS config, it is reloaded on change
+			err = s.server.ListenAndServeTLS("", "")
+		} else {
+			err = s.server.ListenAndServe()
+		}
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			s.log.Fatal().Err(err).Msg("HTTP server start failed")
 		}
@@ -44,13 +108,18 @@ func (s *HTTPServer) MustStart() {
 	}()
 }
 
+// Stop waits for requests in flight up to the shutdown timeout, then closes
+// the remaining connections, such as change feed streams.
 func (s *HTTPServer) Stop() {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
 	defer cancel()
 
-	s.log.Info().Msg("Stopping HTTP server")
+	s.log.Info().Dur("timeout", s.shutdownTimeout).Msg("Stopping HTTP server")
 	if err := s.server.Shutdown(ctx); err != nil {
-		s.log.Fatal().Err(err).Msg("HTTP server shutdown failed")
+		s.log.Warn().Err(err).Msg("HTTP server did not drain in time, closing connections")
+		if err := s.server.Close(); err != nil {
+			s.log.Error().Err(err).Msg("HTTP server close failed")
+		}
 	}
 
 	s.wg.Wait()
@@ -0,0 +1,171 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// TLSReloader serves a TLS configuration read from files and reloads it when
+// they change, so renewed certificates are picked up without a restart. The
+// files are checked on handshakes at most once per interval.
+type TLSReloader struct {
+	log          zerolog.Logger
+	certFile     string
+	keyFile      string
+	clientCAFile string
+	clientAuth   tls.ClientAuthType
+	interval     time.Duration
+
+	mu        sync.Mutex
+	config    *tls.Config
+	modTimes  []time.Time
+	checkedAt time.Time
+}
+
+// NewTLSReloader loads the certificate and key, and when clientCAFile is set
+// the CAs client certificates are verified against.
+func NewTLSReloader(log zerolog.Logger, certFile, keyFile, clientCAFile string, clientAuth tls.ClientAuthType, interval time.Duration) (*TLSReloader, error) {
+	r := &TLSReloader{
+		log:          log.With().Str("module", "tls-reloader").Logger(),
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+		clientAuth:   clientAuth,
+		interval:     interval,
+	}
+
+	config, modTimes, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	r.config, r.modTimes, r.checkedAt = config, modTimes, time.Now()
+
+	return r, nil
+}
+
+// newServerTLSConfig requires both files of the certificate pair, a client
+// CA file makes client certificates required unless clientAuthOptional is set.
+func newServerTLSConfig(log zerolog.Logger, certFile, keyFile, clientCAFile string, clientAuthOptional bool, interval time.Duration) (*tls.Config, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("both the TLS certificate and key files are required")
+	}
+
+	clientAuth := tls.RequireAndVerifyClientCert
+	if clientAuthOptional {
+		clientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	reloader, err := NewTLSReloader(log, certFile, keyFile, clientCAFile, clientAuth, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	return reloader.TLSConfig(), nil
+}
+
+// TLSConfig returns the configuration to give the server, every handshake
+// gets the latest loaded one.
+func (r *TLSReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current(), nil
+		},
+		// Marks the config as having a certificate, handshakes use GetConfigForClient
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &r.current().Certificates[0], nil
+		},
+	}
+}
+
+func (r *TLSReloader) current() *tls.Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) < r.interval {
+		return r.config
+	}
+	r.checkedAt = time.Now()
+
+	modTimes, err := r.stat()
+	if err != nil {
+		r.log.Error().Err(err).Msg("Could not check TLS files, keeping the loaded ones")
+		return r.config
+	}
+	if slices.EqualFunc(modTimes, r.modTimes, time.Time.Equal) {
+		return r.config
+	}
+
+	// A renewal may be caught between writing the certificate and the key,
+	// the next check loads the complete pair
+	config, modTimes, err := r.load()
+	if err != nil {
+		r.log.Error().Err(err).Msg("Could not reload TLS files, keeping the loaded ones")
+		return r.config
+	}
+
+	r.config, r.modTimes = config, modTimes
+	r.log.Info().Str("cert_file", r.certFile).Msg("TLS certificate reloaded")
+
+	return r.config
+}
+
+func (r *TLSReloader) load() (*tls.Config, []time.Time, error) {
+	// Files are stated first, so a change during loading is seen by the next check
+	modTimes, err := r.stat()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load TLS certificate: %w", err)
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+
+	if r.clientCAFile != "" {
+		data, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read client CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, nil, errors.New("client CA file contains no certificates")
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = r.clientAuth
+	}
+
+	return config, modTimes, nil
+}
+
+func (r *TLSReloader) stat() ([]time.Time, error) {
+	files := []string{r.certFile, r.keyFile}
+	if r.clientCAFile != "" {
+		files = append(files, r.clientCAFile)
+	}
+
+	modTimes := make([]time.Time, 0, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes = append(modTimes, info.ModTime())
+	}
+
+	return modTimes, nil
+}
@@ -0,0 +1,201 @@
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/rs/zerolog"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type certFiles struct {
+	cert string
+	key  string
+}
+
+// writeCert stores a self-signed certificate for localhost named after
+// commonName, the modification time is moved forward by age so rewrites are
+// noticed regardless of the file system clock resolution.
+func writeCert(t *testing.T, files certFiles, commonName string, age time.Duration) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writePEM(t, files.cert, "CERTIFICATE", der, age)
+	writePEM(t, files.key, "EC PRIVATE KEY", keyDER, age)
+}
+
+func writePEM(t *testing.T, path, blockType string, data []byte, age time.Duration) {
+	t.Helper()
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Now().Add(age)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newCertFiles(t *testing.T) certFiles {
+	t.Helper()
+
+	dir := t.TempDir()
+	return certFiles{cert: filepath.Join(dir, "tls.crt"), key: filepath.Join(dir, "tls.key")}
+}
+
+// servedName completes a handshake with the reloader's configuration and
+// returns the common name of the certificate the server presented.
+func servedName(t *testing.T, reloader *TLSReloader) string {
+	t.Helper()
+
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	server := tls.Server(serverConn, reloader.TLSConfig())
+	go func() {
+		defer server.Close()
+		_ = server.Handshake()
+	}()
+
+	client := tls.Client(clientConn, &tls.Config{ServerName: "localhost", InsecureSkipVerify: true})
+	if err := client.Handshake(); err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+
+	return client.ConnectionState().PeerCertificates[0].Subject.CommonName
+}
+
+func TestTLSReloaderReloadsChangedCertificate(t *testing.T) {
+	files := newCertFiles(t)
+	writeCert(t, files, "v1", 0)
+
+	reloader, err := NewTLSReloader(zerolog.Nop(), files.cert, files.key, "", tls.NoClientCert, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := servedName(t, reloader); got != "v1" {
+		t.Fatalf("served %q, want v1", got)
+	}
+
+	writeCert(t, files, "v2", time.Minute)
+	if got := servedName(t, reloader); got != "v2" {
+		t.Errorf("served %q after renewal, want v2", got)
+	}
+}
+
+func TestTLSReloaderChecksAtMostOncePerInterval(t *testing.T) {
+	files := newCertFiles(t)
+	writeCert(t, files, "v1", 0)
+
+	reloader, err := NewTLSReloader(zerolog.Nop(), files.cert, files.key, "", tls.NoClientCert, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeCert(t, files, "v2", time.Minute)
+	if got := servedName(t, reloader); got != "v1" {
+		t.Errorf("served %q within the interval, want v1", got)
+	}
+}
+
+func TestTLSReloaderKeepsCertificateWhenReloadFails(t *testing.T) {
+	files := newCertFiles(t)
+	writeCert(t, files, "v1", 0)
+
+	reloader, err := NewTLSReloader(zerolog.Nop(), files.cert, files.key, "", tls.NoClientCert, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A renewal caught halfway, the key does not match the certificate yet
+	key, err := os.ReadFile(files.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeCert(t, files, "v2", time.Minute)
+	if err := os.WriteFile(files.key, key, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := servedName(t, reloader); got != "v1" {
+		t.Errorf("served %q after a failed reload, want v1", got)
+	}
+}
+
+func TestTLSReloaderClientCA(t *testing.T) {
+	files := newCertFiles(t)
+	writeCert(t, files, "server", 0)
+
+	ca := newCertFiles(t)
+	writeCert(t, ca, "client-ca", 0)
+
+	reloader, err := NewTLSReloader(zerolog.Nop(), files.cert, files.key, ca.cert, tls.RequireAndVerifyClientCert, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := reloader.current()
+	if config.ClientAuth != tls.RequireAndVerifyClientCert || config.ClientCAs == nil {
+		t.Errorf("client auth = %v with CAs %v, want verified client certificates", config.ClientAuth, config.ClientCAs)
+	}
+
+	if _, err := NewTLSReloader(zerolog.Nop(), files.cert, files.key, files.key, tls.RequireAndVerifyClientCert, time.Hour); err == nil {
+		t.Error("NewTLSReloader() accepted a client CA file without certificates")
+	}
+}
+
+func TestNewServerTLSConfigRequiresCertificatePair(t *testing.T) {
+	files := newCertFiles(t)
+	writeCert(t, files, "server", 0)
+
+	tests := []struct {
+		name     string
+		certFile string
+		keyFile  string
+	}{
+		{name: "without key", certFile: files.cert},
+		{name: "without certificate", keyFile: files.key},
+		{name: "missing files", certFile: files.cert + ".missing", keyFile: files.key},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newServerTLSConfig(zerolog.Nop(), tt.certFile, tt.keyFile, "", false, time.Minute); err == nil {
+				t.Error("newServerTLSConfig() error = nil, want an error")
+			}
+		})
+	}
+}
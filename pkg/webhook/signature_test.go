@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"id":"1","type":"song.created"}`)
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := Sign("secret", now, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      []byte
+		tolerance time.Duration
+		wantErr   bool
+	}{
+		{name: "valid", secret: "secret", timestamp: timestamp, signature: signature, body: body, tolerance: time.Minute},
+		{name: "wrong secret", secret: "other", timestamp: timestamp, signature: signature, body: body, tolerance: time.Minute, wantErr: true},
+		{name: "tampered body", secret: "secret", timestamp: timestamp, signature: signature, body: []byte(`{}`), tolerance: time.Minute, wantErr: true},
+		{name: "replayed timestamp", secret: "secret", timestamp: strconv.FormatInt(now.Unix()+1, 10), signature: signature, body: body, tolerance: time.Minute, wantErr: true},
+		{name: "missing prefix", secret: "secret", timestamp: timestamp, signature: signature[len(signaturePrefix):], body: body, tolerance: time.Minute, wantErr: true},
+		{name: "malformed timestamp", secret: "secret", timestamp: "yesterday", signature: signature, body: body, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.timestamp, tt.signature, tt.body, tt.tolerance)
+			if tt.wantErr && !errors.Is(err, ErrInvalidSignature) {
+				t.Fatalf("Verify() error = %v, want %v", err, ErrInvalidSignature)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("Verify() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestVerifyTolerance(t *testing.T) {
+	body := []byte(`{}`)
+	sentAt := time.Now().Add(-10 * time.Minute)
+	timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+	signature := Sign("secret", sentAt, body)
+
+	if err := Verify("secret", timestamp, signature, body, 5*time.Minute); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("Verify() of a stale request error = %v, want %v", err, ErrInvalidSignature)
+	}
+	if err := Verify("secret", timestamp, signature, body, 0); err != nil {
+		t.Fatalf("Verify() without tolerance error = %v, want nil", err)
+	}
+}